@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// captureOutput runs command and returns its stdout. Without useShell the
+// command line is split into words like a shell would, honouring quotes and
+// backslashes, but nothing is expanded. A non-zero exit is an error.
+func captureOutput(command string, useShell bool) ([]byte, error) {
+	var argv []string
+	if useShell {
+		if runtime.GOOS == "windows" {
+			argv = []string{"cmd", "/C", command}
+		} else {
+			argv = []string{"/bin/sh", "-c", command}
+		}
+	} else {
+		var err error
+		argv, err = splitCommand(command)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(argv) == 0 {
+		return nil, errors.New("the command is empty")
+	}
+
+	var stdout bytes.Buffer
+	child := exec.Command(argv[0], argv[1:]...)
+	child.Stdin = os.Stdin
+	child.Stdout = &stdout
+	child.Stderr = os.Stderr
+
+	if err := child.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%s exited with status %d", argv[0], exitErr.ExitCode())
+		}
+
+		return nil, fmt.Errorf("running %s failed: %w", argv[0], err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// splitCommand splits s into words. Single quotes keep their content
+// verbatim, double quotes allow backslash escapes of " and \, and outside
+// quotes a backslash escapes the next character.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	quote := rune(0)
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command", quote)
+	}
+
+	if escaped {
+		return nil, errors.New("command ends with a backslash")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// closingQuote returns the index of the double quote that closes the JSON
+// string at the start of s, or -1 if it is not closed.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// extractJSONField returns the value at a jq-style path such as
+// .credentials.token, .items[0].value or .["odd key"] in the JSON document
+// data. Quoted keys must be JSON double-quoted strings. Strings are returned
+// as is, other values as compact JSON.
+func extractJSONField(data []byte, path string) (string, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("output is not valid JSON: %w", err)
+	}
+
+	rest := strings.TrimSpace(path)
+	if !strings.HasPrefix(rest, ".") {
+		return "", fmt.Errorf("invalid path %q, it must start with a dot", path)
+	}
+
+	for rest != "" && rest != "." {
+		var key string
+		index := -1
+
+		switch {
+		case strings.HasPrefix(rest, ".[") || strings.HasPrefix(rest, "["):
+			rest = strings.TrimPrefix(strings.TrimPrefix(rest, "."), "[")
+			var inner string
+			if strings.HasPrefix(rest, "\"") {
+				end := closingQuote(rest)
+				if end < 0 {
+					return "", fmt.Errorf("invalid path %q, missing closing quote", path)
+				}
+
+				inner, rest = rest[:end+1], rest[end+1:]
+			} else if end := strings.Index(rest, "]"); end >= 0 {
+				inner, rest = rest[:end], rest[end:]
+			}
+
+			if !strings.HasPrefix(rest, "]") {
+				return "", fmt.Errorf("invalid path %q, missing ]", path)
+			}
+
+			rest = rest[1:]
+			if strings.HasPrefix(inner, "\"") {
+				if err := json.Unmarshal([]byte(inner), &key); err != nil {
+					return "", fmt.Errorf("invalid path %q, bad quoted key %s", path, inner)
+				}
+			} else if n, err := strconv.Atoi(inner); err == nil && n >= 0 {
+				index = n
+			} else {
+				return "", fmt.Errorf("invalid path %q, expected a double-quoted key or an index in brackets", path)
+			}
+		case strings.HasPrefix(rest, ".\""):
+			rest = rest[1:]
+			end := closingQuote(rest)
+			if end < 0 {
+				return "", fmt.Errorf("invalid path %q, missing closing quote", path)
+			}
+
+			if err := json.Unmarshal([]byte(rest[:end+1]), &key); err != nil {
+				return "", fmt.Errorf("invalid path %q, bad quoted key %s", path, rest[:end+1])
+			}
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			key = rest[:end]
+			rest = rest[end:]
+			if key == "" {
+				return "", fmt.Errorf("invalid path %q, empty key", path)
+			}
+		default:
+			return "", fmt.Errorf("invalid path %q near %q", path, rest)
+		}
+
+		if index >= 0 {
+			list, ok := v.([]interface{})
+			if !ok || index >= len(list) {
+				return "", fmt.Errorf("path %s does not exist in the output", path)
+			}
+			v = list[index]
+			continue
+		}
+
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("path %s does not exist in the output", path)
+		}
+
+		if v, ok = obj[key]; !ok {
+			return "", fmt.Errorf("path %s does not exist in the output", path)
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", fmt.Errorf("path %s is null in the output", path)
+	default:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+}
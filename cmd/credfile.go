@@ -0,0 +1,524 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/99designs/keyring"
+	"github.com/frostyeti/osv/cmd/config"
+	"github.com/frostyeti/osv/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// credfileCmd represents the credfile command
+var credfileCmd = &cobra.Command{
+	Use:   "credfile <type> [--entry <mapping>]... [-- <command> [args...]]",
+	Short: "Generate a credential file such as .pgpass or .netrc from the keyring",
+	Long: `Generate a credential file for a tool that reads passwords from its own
+file format, escaping each value the way that tool expects.
+
+Supported types:
+  pgpass   PostgreSQL password file (PGPASSFILE)
+  netrc    .netrc used by curl, git and ftp clients (NETRC)
+  npmrc    npm user config with registry credentials (NPM_CONFIG_USERCONFIG)
+  pip      pip.conf with an authenticated index URL (PIP_CONFIG_FILE)
+
+Each line of the file is described by a mapping of comma separated
+field=value pairs, where key names the secret holding the password:
+
+  pgpass   host, port, database, user, key    (host, port and database default to *)
+  netrc    host, user, key, account
+  npmrc    host, key, user, scope             (token auth unless user is set)
+  pip      host, key, user                    (the first entry is the index-url)
+
+Mappings are given with --entry, with the --host, --user and --key shortcuts
+for a single entry, or read from the multi-line config value
+credfile.<type>, one mapping per line.
+
+When --output is given the file is written atomically with 0600 permissions,
+otherwise it is printed. When a command is given after --, the file is
+written to a private temporary directory instead, exported to the command in
+the variable the tool reads, and removed when the command exits. osv run
+--credfile <type> does the same using the mappings from the config. The
+directory is created under $XDG_RUNTIME_DIR, or the temporary directory, which
+must be on a memory-backed file system (tmpfs) unless --allow-disk is set.
+
+Examples:
+  # Write ~/.pgpass for one database
+  osv credfile pgpass --host db.example.com --port 5432 --user app --key db-pass -o ~/.pgpass
+
+  # Authenticate npm against a private registry for one install only
+  osv credfile npmrc --entry host=npm.pkg.github.com,scope=@acme,key=gh-token -- npm ci
+
+  # Configure the mappings once and use them with osv run
+  osv config set credfile.netrc "host=api.example.com,user=ci,key=api-token"
+  osv run --credfile netrc -- curl --netrc https://api.example.com/`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		allowDisk, _ := cmd.Flags().GetBool("allow-disk")
+
+		command := []string{}
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			if dash != 1 {
+				Error(cmd, "exactly one credential file type must be given before --\n")
+				osExit(1)
+			}
+			command = args[dash:]
+		} else if len(args) != 1 {
+			Error(cmd, "exactly one credential file type must be given\n")
+			osExit(1)
+		}
+
+		ct, ok := credfileTypes[args[0]]
+		if !ok {
+			Error(cmd, "unknown credential file type %q (%s)\n", args[0], strings.Join(credfileTypeNames(), ", "))
+			osExit(1)
+		}
+
+		entries, err := credfileEntries(cmd, args[0])
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		kr, err := openKeyring(cmd)
+		if err != nil {
+			Error(cmd, "opening keyring failed: %v\n", err)
+			osExit(exitBackend)
+		}
+
+		data, code, err := renderCredfile(ct, entries, kr)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(code)
+		}
+
+		if len(command) > 0 {
+			m, err := writeEphemeralCredfile(ct, data, allowDisk)
+			if err != nil {
+				Error(cmd, "%v\n", err)
+				osExit(1)
+			}
+
+			env := append(os.Environ(), ct.envVar+"="+m.files[0])
+			code := runChild(cmd, command, env)
+			m.remove(cmd)
+			osExit(code)
+			return
+		}
+
+		if output == "" {
+			_, _ = os.Stdout.WriteString(data)
+			return
+		}
+
+		if err := utils.WriteFileAtomic(output, []byte(data), 0600); err != nil {
+			Error(cmd, "writing %s failed: %v\n", output, err)
+			osExit(1)
+		}
+
+		Ok(cmd, "wrote %s credentials to %s\n", args[0], output)
+	},
+}
+
+// credEntry is one mapping of credential file fields to values. The secret
+// named by the key field is stored under "secret" before rendering.
+type credEntry map[string]string
+
+// credfileType describes one supported credential file format.
+type credfileType struct {
+	// fileName is the name of the file written in ephemeral mode.
+	fileName string
+	// envVar is the variable the tool reads the file location from.
+	envVar string
+	// fields lists the accepted mapping fields and required the mandatory
+	// ones, besides key.
+	fields   []string
+	required []string
+	render   func(entries []credEntry) (string, error)
+}
+
+var credfileTypes = map[string]credfileType{
+	"pgpass": {
+		fileName: "pgpass",
+		envVar:   "PGPASSFILE",
+		fields:   []string{"host", "port", "database", "user", "key"},
+		required: []string{"user"},
+		render:   renderPgpass,
+	},
+	"netrc": {
+		fileName: "netrc",
+		envVar:   "NETRC",
+		fields:   []string{"host", "user", "key", "account"},
+		required: []string{"host"},
+		render:   renderNetrc,
+	},
+	"npmrc": {
+		fileName: "npmrc",
+		envVar:   "NPM_CONFIG_USERCONFIG",
+		fields:   []string{"host", "key", "user", "scope"},
+		required: []string{"host"},
+		render:   renderNpmrc,
+	},
+	"pip": {
+		fileName: "pip.conf",
+		envVar:   "PIP_CONFIG_FILE",
+		fields:   []string{"host", "key", "user"},
+		required: []string{"host"},
+		render:   renderPip,
+	},
+}
+
+func credfileTypeNames() []string {
+	names := make([]string, 0, len(credfileTypes))
+	for name := range credfileTypes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// credfileEntries collects the mappings for a credential file type from the
+// flags, falling back to the credfile.<type> config value.
+func credfileEntries(cmd *cobra.Command, typeName string) ([]credEntry, error) {
+	var specs []string
+	if cmd.Flags().Lookup("entry") != nil {
+		specs, _ = cmd.Flags().GetStringArray("entry")
+		if key, _ := cmd.Flags().GetString("key"); key != "" {
+			spec := "key=" + key
+			for _, field := range []string{"host", "port", "database", "user"} {
+				if v, _ := cmd.Flags().GetString(field); v != "" {
+					spec += "," + field + "=" + v
+				}
+			}
+			specs = append(specs, spec)
+		}
+	}
+
+	if len(specs) == 0 {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return nil, fmt.Errorf("loading config failed: %w", err)
+		}
+
+		v, _ := cfg.Get("credfile." + typeName)
+		for _, line := range strings.Split(v, "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				specs = append(specs, line)
+			}
+		}
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no %s entries given, use --entry or set credfile.%s in the config", typeName, typeName)
+	}
+
+	ct := credfileTypes[typeName]
+	entries := make([]credEntry, 0, len(specs))
+	for _, spec := range specs {
+		entry, err := parseCredEntry(spec, ct)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseCredEntry parses a field=value,field=value mapping.
+func parseCredEntry(spec string, ct credfileType) (credEntry, error) {
+	entry := credEntry{}
+	for _, pair := range strings.Split(spec, ",") {
+		field, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid mapping %q, expected field=value pairs", spec)
+		}
+
+		if !slices.Contains(ct.fields, field) {
+			return nil, fmt.Errorf("unknown field %q in mapping %q (%s)", field, spec, strings.Join(ct.fields, ", "))
+		}
+
+		entry[field] = value
+	}
+
+	for _, field := range append([]string{"key"}, ct.required...) {
+		if entry[field] == "" {
+			return nil, fmt.Errorf("mapping %q has no %s", spec, field)
+		}
+	}
+
+	return entry, nil
+}
+
+// renderCredfile resolves the secret of every entry and renders the file. On
+// failure it also returns the exit code to use.
+func renderCredfile(ct credfileType, entries []credEntry, kr keyring.Keyring) (string, int, error) {
+	for _, entry := range entries {
+		item, err := kr.Get(entry["key"])
+		if err != nil {
+			code := exitBackend
+			if errors.Is(err, keyring.ErrKeyNotFound) {
+				code = exitMissing
+			}
+
+			return "", code, fmt.Errorf("getting secret %s failed: %w", entry["key"], err)
+		}
+
+		entry["secret"] = string(item.Data)
+	}
+
+	data, err := ct.render(entries)
+	if err != nil {
+		return "", 1, err
+	}
+
+	return data, 0, nil
+}
+
+// writeEphemeralCredfile writes data to a new private directory under
+// $XDG_RUNTIME_DIR, or the temporary directory when it is not set. The
+// directory must be on a memory-backed file system unless allowDisk is set.
+func writeEphemeralCredfile(ct credfileType, data string, allowDisk bool) (*materialized, error) {
+	base := os.Getenv("XDG_RUNTIME_DIR")
+	if base == "" {
+		base = os.TempDir()
+	}
+
+	if !allowDisk {
+		if ok, err := memoryBacked(base); err != nil || !ok {
+			return nil, fmt.Errorf("%s is not on a memory-backed file system, set XDG_RUNTIME_DIR to a tmpfs directory or use --allow-disk", base)
+		}
+	}
+
+	dir, err := os.MkdirTemp(base, "osv-credfile-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary directory failed: %w", err)
+	}
+
+	m := &materialized{dir: dir, created: true}
+	if err := m.write(ct.fileName, []byte(data)); err != nil {
+		_ = os.Remove(dir)
+		return nil, fmt.Errorf("writing %s failed: %w", filepath.Join(dir, ct.fileName), err)
+	}
+
+	return m, nil
+}
+
+// prepareCredfile writes the credential file configured for typeName to a
+// private temporary directory and returns the NAME=path variable pointing the
+// tool at it. It is used by osv run --credfile.
+func prepareCredfile(cmd *cobra.Command, typeName string, kr keyring.Keyring) (string, *materialized, error) {
+	ct, ok := credfileTypes[typeName]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown credential file type %q (%s)", typeName, strings.Join(credfileTypeNames(), ", "))
+	}
+
+	entries, err := credfileEntries(cmd, typeName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, _, err := renderCredfile(ct, entries, kr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	allowDisk, _ := cmd.Flags().GetBool("allow-disk")
+	m, err := writeEphemeralCredfile(ct, data, allowDisk)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ct.envVar + "=" + m.files[0], m, nil
+}
+
+// renderPgpass renders hostname:port:database:username:password lines,
+// escaping colons and backslashes as libpq expects.
+func renderPgpass(entries []credEntry) (string, error) {
+	escape := strings.NewReplacer(`\`, `\\`, `:`, `\:`)
+
+	var b strings.Builder
+	for _, e := range entries {
+		fields := []string{orDefault(e["host"], "*"), orDefault(e["port"], "*"), orDefault(e["database"], "*"), e["user"], e["secret"]}
+		for i, f := range fields {
+			if strings.ContainsAny(f, "\r\n") {
+				return "", fmt.Errorf("secret %s: pgpass values cannot contain newlines", e["key"])
+			}
+
+			if i > 0 {
+				b.WriteByte(':')
+			}
+
+			b.WriteString(escape.Replace(f))
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String(), nil
+}
+
+// renderNetrc renders one machine line per entry, quoting tokens that contain
+// whitespace, quotes or backslashes.
+func renderNetrc(entries []credEntry) (string, error) {
+	var b strings.Builder
+	for _, e := range entries {
+		pairs := [][2]string{{"machine", e["host"]}}
+		if e["user"] != "" {
+			pairs = append(pairs, [2]string{"login", e["user"]})
+		}
+		pairs = append(pairs, [2]string{"password", e["secret"]})
+		if e["account"] != "" {
+			pairs = append(pairs, [2]string{"account", e["account"]})
+		}
+
+		for i, p := range pairs {
+			if strings.ContainsAny(p[1], "\r\n") {
+				return "", fmt.Errorf("secret %s: netrc values cannot contain newlines", e["key"])
+			}
+
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+
+			b.WriteString(p[0] + " " + netrcToken(p[1]))
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String(), nil
+}
+
+func netrcToken(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// renderNpmrc renders registry credentials keyed by the registry's
+// protocol-relative URL, as npm expects them.
+func renderNpmrc(entries []credEntry) (string, error) {
+	var b strings.Builder
+	for _, e := range entries {
+		registry := e["host"]
+		if !strings.Contains(registry, "://") {
+			registry = "https://" + registry
+		}
+
+		u, err := url.Parse(registry)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("invalid npm registry %q", e["host"])
+		}
+
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+
+		if e["scope"] != "" {
+			scope := e["scope"]
+			if !strings.HasPrefix(scope, "@") {
+				scope = "@" + scope
+			}
+			b.WriteString(scope + ":registry=" + u.String() + "\n")
+		}
+
+		prefix := "//" + u.Host + u.Path + ":"
+		if e["user"] != "" {
+			b.WriteString(prefix + "username=" + iniValue(e["user"]) + "\n")
+			b.WriteString(prefix + "_password=" + base64.StdEncoding.EncodeToString([]byte(e["secret"])) + "\n")
+			continue
+		}
+
+		b.WriteString(prefix + "_authToken=" + iniValue(e["secret"]) + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// iniValue quotes values npm's ini parser would otherwise alter: comments,
+// surrounding whitespace, quotes and line breaks. Quoted values are JSON
+// strings.
+func iniValue(s string) string {
+	if s != "" && strings.TrimSpace(s) == s && !strings.ContainsAny(s, ";#\"'\\\r\n") {
+		return s
+	}
+
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// renderPip renders a [global] section whose index-url is the first entry
+// and whose extra-index-url lists the others, with the credentials embedded
+// in the URLs.
+func renderPip(entries []credEntry) (string, error) {
+	urls := make([]string, 0, len(entries))
+	for _, e := range entries {
+		index := e["host"]
+		if !strings.Contains(index, "://") {
+			index = "https://" + index
+		}
+
+		u, err := url.Parse(index)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("invalid pip index URL %q", e["host"])
+		}
+
+		if e["user"] != "" {
+			u.User = url.UserPassword(e["user"], e["secret"])
+		} else {
+			u.User = url.User(e["secret"])
+		}
+
+		urls = append(urls, u.String())
+	}
+
+	var b strings.Builder
+	b.WriteString("[global]\n")
+	b.WriteString("index-url = " + urls[0] + "\n")
+	if len(urls) > 1 {
+		b.WriteString("extra-index-url =\n")
+		for _, u := range urls[1:] {
+			b.WriteString("    " + u + "\n")
+		}
+	}
+
+	return b.String(), nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(credfileCmd)
+	service := os.Getenv("OSV_SERVICE")
+	credfileCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
+	credfileCmd.Flags().StringArrayP("entry", "e", []string{}, "Mapping of fields for one line of the file, as field=value,... (can be specified multiple times)")
+	credfileCmd.Flags().String("host", "", "Host of a single entry (registry or index URL for npmrc and pip)")
+	credfileCmd.Flags().String("port", "", "Port of a single entry (pgpass)")
+	credfileCmd.Flags().String("database", "", "Database of a single entry (pgpass)")
+	credfileCmd.Flags().String("user", "", "User of a single entry")
+	credfileCmd.Flags().StringP("key", "k", "", "Secret holding the password of a single entry")
+	credfileCmd.Flags().StringP("output", "o", "", "Write the file to this path instead of stdout")
+	credfileCmd.Flags().Bool("allow-disk", false, "Allow writing the file for a command to a directory that is not memory-backed")
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/99designs/keyring"
+	"golang.org/x/term"
+)
+
+// encodingPrefix marks the description of items stored from encoded input.
+// The rest of the description names the encoding, e.g. osv:encoding=base64.
+const encodingPrefix = "osv:encoding="
+
+// stdoutIsTerminal reports whether stdout is a terminal. Tests replace it.
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// validateEncoding checks an --encoding flag value. The empty string and raw
+// both mean the value is used as is.
+func validateEncoding(encoding string) error {
+	switch encoding {
+	case "", "raw", "base64", "hex":
+		return nil
+	default:
+		return fmt.Errorf("unknown encoding %q (raw, base64, hex)", encoding)
+	}
+}
+
+// decodeValue decodes s from encoding. Whitespace is ignored in encoded input
+// so that wrapped base64 and trailing newlines from files are accepted.
+func decodeValue(encoding, s string) ([]byte, error) {
+	switch encoding {
+	case "base64":
+		s = strings.Join(strings.Fields(s), "")
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			b, err = base64.RawStdEncoding.DecodeString(s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 value: %w", err)
+		}
+		return b, nil
+	case "hex":
+		b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex value: %w", err)
+		}
+		return b, nil
+	default:
+		return []byte(s), nil
+	}
+}
+
+// encodeValue encodes data for output.
+func encodeValue(encoding string, data []byte) string {
+	switch encoding {
+	case "base64":
+		return base64.StdEncoding.EncodeToString(data)
+	case "hex":
+		return hex.EncodeToString(data)
+	default:
+		return string(data)
+	}
+}
+
+// itemEncoding returns the encoding an item was stored from, or "" for items
+// set from plain text.
+func itemEncoding(item keyring.Item) string {
+	if encoding, ok := strings.CutPrefix(item.Description, encodingPrefix); ok {
+		return encoding
+	}
+
+	return ""
+}
+
+// isBinary reports whether an item holds binary data that would be mangled
+// when printed as text.
+func isBinary(item keyring.Item) bool {
+	return itemEncoding(item) != "" || !utf8.Valid(item.Data)
+}
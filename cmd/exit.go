@@ -3,3 +3,11 @@ package cmd
 import "os"
 
 var osExit = os.Exit
+
+// Exit codes that tell missing secrets apart from keyring failures and
+// conditional writes that were refused.
+const (
+	exitMissing  = 2
+	exitBackend  = 3
+	exitConflict = 4
+)
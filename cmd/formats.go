@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/frostyeti/osv/format"
+	"github.com/spf13/cobra"
+)
+
+// formatsCmd represents the formats command
+var formatsCmd = &cobra.Command{
+	Use:   "formats",
+	Short: "List the output formats available to osv get",
+	Long: `List the output formats available to osv get --format.
+
+Besides the built-in formats, any executable named osv-format-<name> on the
+PATH is available as --format <name>. It receives a JSON document on stdin:
+
+  {
+    "format": "<name>",
+    "entries": [{"key": "db-password", "name": "DB_PASSWORD", "value": "..."}],
+    "options": {...}
+  }
+
+and whatever it writes to stdout becomes the output of osv get.
+
+Examples:
+  # List all formats
+  osv formats`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tALIASES\tDESCRIPTION")
+		for _, f := range format.Formatters() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", f.Name(), strings.Join(f.Aliases(), ", "), f.Description())
+		}
+
+		for _, f := range format.ExternalFormatters() {
+			fmt.Fprintf(w, "%s\t\t%s\n", f.Name(), f.Description())
+		}
+
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(formatsCmd)
+}
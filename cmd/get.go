@@ -4,14 +4,22 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/99designs/keyring"
 	"github.com/atotto/clipboard"
 	"github.com/frostyeti/go/dotenv"
+	"github.com/frostyeti/osv/format"
 	"github.com/frostyeti/osv/internal/utils"
+	"github.com/gobwas/glob"
 	"github.com/spf13/cobra"
 )
 
@@ -32,127 +40,633 @@ Examples:
   # Get multiple secrets
   osv get --key secret1 --key secret2
 
+  # Get every secret matching a glob pattern
+  osv get 'app-x-*' --format dotenv
+
+  # Get a secret whose name contains glob characters
+  osv get 'weird*name' --literal
+
+  # Write a .env file readable only by you, or update one in place
+  osv get db-user db-pass --format dotenv --out .env
+  osv get db-pass --format dotenv --out .env --merge
+
+  # Skip secrets that may be missing, or fall back to a default
+  osv get db-pass 'sentry-dsn?' --format dotenv
+  osv get db-pass --optional sentry-dsn --default log-level=info --format dotenv
+
+  # Output everything that can be read, reporting failures as JSON on stderr
+  osv get 'app-*' legacy-token --continue-on-error --error-format json
+
+  # Print a binary secret safely, or write the raw bytes to a file
+  osv get keystore --encoding base64
+  osv get keystore > keystore.p12
+
   # Get secrets with different output formats
   osv get secret1 --format json
   osv get --key secret1 --format sh
-  osv get --key secret1 --format dotenv`,
+  osv get --key secret1 --format dotenv
+
+  # Structured data formats are keyed by secret name
+  osv get db.user db.password --format yaml --nest > values.secret.yaml
+  osv get db.user db.password --format toml --nest
+  osv get db-user db-password --format properties
+  osv get db_user db_password --format tfvars > secrets.auto.tfvars
+
+  # Load secrets into other shells
+  osv get secret1 --format fish | source
+  osv get secret1 --format nu | save -f secrets.nu; source secrets.nu
+  osv get secret1 --format cmd > secrets.cmd && call secrets.cmd
+  eval (osv get secret1 --format elvish | slurp)
+
+  # Include secrets in a Makefile
+  osv get secret1 --format make > secrets.mk
+
+  # Pass secrets to docker run, or to compose as one file per secret
+  docker run --env-file <(osv get db-user db-pass --format docker-env) app
+  osv get db-pass --case snake --format compose-secrets --dir ./secrets >> compose.secrets.yaml
+
+  # Expose secrets to later steps and as step outputs in GitHub Actions
+  osv get db-pass --format github --github-target both
+
+  # Other CI systems
+  osv get db-pass --format gitlab > build.env          # dotenv report artifact
+  osv get db-pass --format buildkite > hooks/environment
+  osv get db-pass --format circleci                    # appends to $BASH_ENV
+  osv get db-pass --format jenkins > secrets.properties  # EnvInject properties file
+
+  # Provision credentials for a systemd service
+  sudo osv get db-pass --format systemd-creds --dir /etc/credstore/app --owner root
+  osv get db-pass --format systemd-creds --systemd-dropin > override.conf
+
+  # Hash a password for nginx basic auth or /etc/shadow without printing it
+  osv get admin-pass --format htpasswd --username admin >> /etc/nginx/htpasswd
+  osv get root-pass --format sha512-crypt --rounds 656000
+
+  # Apply secrets to a Kubernetes cluster without writing them to disk
+  osv get db-user db-pass --format k8s-secret --name db --namespace prod | kubectl apply -f -
+
+Optional keys:
+  A trailing "?" marks a key as optional, so 'sentry-dsn?' is skipped when
+  missing. With --literal, or when the rest of the key has no glob characters
+  (*, ?, [ or {), the "?" is always the marker. Otherwise it is part of the
+  pattern, so 'app-*-?' matches app-x-1. To match one trailing character in an
+  otherwise plain pattern, wrap it in braces, e.g. '{app-?}'. To fetch a key
+  that really ends in "?", use --literal and add a second "?", e.g. 'what??'.
+
+Variable names:
+  Shell, dotenv and CI formats export each secret under a variable name that
+  is derived from the secret name, e.g. "db-password" becomes DB_PASSWORD.
+  Output follows the order in which keys were requested unless --sort is set.
+
+  # Export a secret under an explicit name
+  osv get --as DATABASE_URL=prod-db-url --format sh
+
+  # Strip a common prefix and add another one
+  osv get app-db-user app-db-pass --strip-prefix app- --prefix MYAPP_ --format dotenv
+
+  # Keep the generated names in snake_case
+  osv get db-user db-pass --case snake --format dotenv
+
+Exit codes:
+  0  all secrets were read
+  1  invalid arguments or output failure
+  2  one or more required secrets are missing
+  3  the keyring could not be opened or read`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		keys, _ := cmd.Flags().GetStringSlice("key")
-		format, _ := cmd.Flags().GetString("format")
+		formatName, _ := cmd.Flags().GetString("format")
 		clip, _ := cmd.Flags().GetBool("clip")
+		aliases, _ := cmd.Flags().GetStringArray("as")
+		sortKeys, _ := cmd.Flags().GetBool("sort")
+		literal, _ := cmd.Flags().GetBool("literal")
+		optionalKeys, _ := cmd.Flags().GetStringSlice("optional")
+		defaultPairs, _ := cmd.Flags().GetStringArray("default")
+		continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+		errorFormat, _ := cmd.Flags().GetString("error-format")
+		out, _ := cmd.Flags().GetString("out")
+		modeText, _ := cmd.Flags().GetString("mode")
+		appendOut, _ := cmd.Flags().GetBool("append")
+		mergeOut, _ := cmd.Flags().GetBool("merge")
+		encoding, _ := cmd.Flags().GetString("encoding")
 
 		if len(args) > 0 {
 			keys = append(keys, args...)
 		}
 
-		if format == "" {
-			format = "text"
+		keys, optional := parseOptionalKeys(keys, optionalKeys, literal)
+		keys, defaults, err := parseDefaults(keys, defaultPairs)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		if formatName == "" {
+			formatName = "text"
 		}
 
-		if len(keys) == 0 {
+		naming, err := newNameOptions(cmd)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		if err := validateEncoding(encoding); err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		outMode, err := strconv.ParseUint(modeText, 8, 32)
+		if err != nil || outMode > 0777 {
+			Error(cmd, "invalid --mode %q (expected an octal permission such as 0600)\n", modeText)
+			osExit(1)
+		}
+
+		outputMode := "replace"
+		switch {
+		case appendOut && mergeOut:
+			Error(cmd, "--append and --merge are mutually exclusive\n")
+			osExit(1)
+		case appendOut:
+			outputMode = "append"
+		case mergeOut:
+			outputMode = "merge"
+		}
+
+		if outputMode != "replace" && out == "" {
+			Error(cmd, "--append and --merge require --out\n")
+			osExit(1)
+		}
+
+		if len(keys) == 0 && len(aliases) == 0 {
 			Error(cmd, "at least one --key must be provided\n")
 			osExit(1)
 		}
 
+		f, ok := format.Lookup(formatName)
+		if !ok {
+			Error(cmd, "unknown format %q, run 'osv formats' to list the available formats\n", formatName)
+			osExit(1)
+		}
+
+		if outputMode == "merge" && f.Name() != "dotenv" {
+			Error(cmd, "--merge is only supported with the dotenv format\n")
+			osExit(1)
+		}
+
 		kr, err := openKeyring(cmd)
 		if err != nil {
 			Error(cmd, "opening keyring failed: %v\n", err)
-			osExit(1)
+			osExit(exitBackend)
 		}
 
-		values := map[string]string{}
-		var firstVal string
-		for i, key := range keys {
-			item, err := kr.Get(key)
+		var failures []keyFailure
+		if !literal {
+			var unmatched []string
+			keys, unmatched, err = expandKeys(kr, keys, optional)
 			if err != nil {
-				Error(cmd, "getting secret %s failed: %v\n", key, err)
-				osExit(1)
+				Error(cmd, "%v\n", err)
+				osExit(exitBackend)
 			}
-			val := string(item.Data)
-			values[key] = val
-			if i == 0 {
-				firstVal = val
+
+			for _, pattern := range unmatched {
+				failures = append(failures, keyFailure{Key: pattern, Error: keyring.ErrKeyNotFound.Error(), Missing: true})
+			}
+		}
+
+		requests, err := resolveNames(keys, aliases, naming)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		if sortKeys {
+			sort.SliceStable(requests, func(i, j int) bool {
+				return requests[i].Key < requests[j].Key
+			})
+		}
+
+		entries := make([]format.Entry, 0, len(requests))
+		var binary []string
+		for _, req := range requests {
+			item, err := kr.Get(req.Key)
+			if err == nil {
+				if (encoding == "" || encoding == "raw") && isBinary(item) {
+					binary = append(binary, req.Key)
+				} else if isSingleLine(string(item.Data)) {
+					Warning(cmd, "secret %s ends with a newline, set it again with --trim-newline if that is unintended\n", req.Key)
+				}
+
+				req.Value = encodeValue(encoding, item.Data)
+				entries = append(entries, req)
+				continue
+			}
+
+			missing := errors.Is(err, keyring.ErrKeyNotFound)
+			if missing {
+				if v, ok := defaults[req.Key]; ok {
+					req.Value = encodeValue(encoding, []byte(v))
+					entries = append(entries, req)
+					continue
+				}
+
+				if optional[req.Key] {
+					continue
+				}
 			}
+
+			failures = append(failures, keyFailure{Key: req.Key, Error: err.Error(), Missing: missing})
+		}
+
+		if len(failures) > 0 && !continueOnError {
+			Error(cmd, "getting secret %s failed: %v\n", failures[0].Key, failures[0].Error)
+			osExit(failures[0].exitCode())
 		}
 
 		if clip {
-			if err := clipboard.WriteAll(firstVal); err != nil {
+			if len(entries) == 0 {
+				Error(cmd, "no secret to copy to clipboard\n")
+				osExit(exitMissing)
+			}
+
+			if err := writeClipboard(entries[0].Value); err != nil {
 				Error(cmd, "copying to clipboard failed: %v\n", err)
 				osExit(1)
 			}
 			Ok(cmd, "copied to clipboard\n")
+			exitOnFailures(cmd, failures, errorFormat)
 			return
 		}
 
-		switch format {
-		case "json":
-			b, err := json.MarshalIndent(values, "", "  ")
-			if err != nil {
-				Error(cmd, "marshaling secrets to JSON failed: %v\n", err)
-				osExit(1)
+		if out == "" && f.Name() != "json" && f.Name() != "fingerprint" && stdoutIsTerminal() {
+			for _, key := range binary {
+				Warning(cmd, "secret %s contains binary data, use --encoding base64 or hex to print it safely\n", key)
 			}
-			fmt.Println(string(b))
+		}
 
-		case "null-terminated", "null":
-			for _, v := range values {
-				fmt.Printf("%s\x00", v)
+		if out == "" {
+			if err := f.Format(os.Stdout, entries, formatOptions(cmd)); err != nil {
+				Error(cmd, "%v\n", err)
+				osExit(1)
 			}
-
-		case "sh", "bash", "zsh":
-			for k, v := range values {
-				key := utils.ScreamingSnakeCase(k)
-				fmt.Printf("export %s='%s'\n", key, v)
+		} else {
+			if err := writeOutput(out, f, entries, formatOptions(cmd), outMode, outputMode); err != nil {
+				Error(cmd, "writing %s failed: %v\n", out, err)
+				osExit(1)
 			}
 
-		case "powershell", "pwsh":
-			for k, v := range values {
-				key := utils.ScreamingSnakeCase(k)
-				fmt.Printf("$Env:%s='%s'\n", key, v)
-			}
+			Ok(cmd, "wrote %d secret(s) to %s\n", len(entries), out)
+		}
+
+		exitOnFailures(cmd, failures, errorFormat)
+	},
+}
+
+// writeClipboard copies text to the system clipboard. Tests replace it.
+var writeClipboard = clipboard.WriteAll
+
+// exitOnFailures reports failures, if any, and exits with the highest exit
+// code among them.
+func exitOnFailures(cmd *cobra.Command, failures []keyFailure, errorFormat string) {
+	if len(failures) == 0 {
+		return
+	}
+
+	reportFailures(cmd, failures, errorFormat)
+	code := exitMissing
+	for _, failure := range failures {
+		code = max(code, failure.exitCode())
+	}
+	osExit(code)
+}
+
+// writeOutput formats entries into the file at path, atomically and with the
+// given permissions. The file is replaced, appended to, or, for dotenv, merged
+// with the existing variables depending on mode. Symlinks are refused rather
+// than followed.
+func writeOutput(path string, f format.Formatter, entries []format.Entry, opts format.Options, perm uint64, mode string) error {
+	info, err := os.Lstat(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if exists && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to write through symlink %s", path)
+	}
+
+	if exists && !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+
+	var existing []byte
+	if exists && mode != "replace" {
+		existing, err = os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	var data []byte
+	if mode == "merge" {
+		merged, err := mergeDotenv(string(existing), entries)
+		if err != nil {
+			return err
+		}
+		data = []byte(merged)
+	} else {
+		var buf bytes.Buffer
+		if err := f.Format(&buf, entries, opts); err != nil {
+			return err
+		}
 
-		case "dotenv", "env", ".env":
-			doc := dotenv.NewDoc()
-			for k, v := range values {
-				key := utils.ScreamingSnakeCase(k)
-				doc.Set(key, v)
+		if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+			existing = append(existing, '\n')
+		}
+		data = append(existing, buf.Bytes()...)
+	}
+
+	return utils.WriteFileAtomic(path, data, os.FileMode(perm))
+}
+
+// mergeDotenv sets each entry in the dotenv document content. Existing
+// variables are updated in place, keeping comments and layout, and new ones
+// are added at the end.
+func mergeDotenv(content string, entries []format.Entry) (string, error) {
+	doc, err := dotenv.Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("parsing existing dotenv file failed: %w", err)
+	}
+
+	values := map[string]string{}
+	for _, e := range entries {
+		values[e.Name] = e.Value
+	}
+
+	merged := dotenv.NewDoc()
+	for _, el := range doc.ToArray() {
+		if el.Type == dotenv.VARIABLE && el.Key != nil {
+			if v, ok := values[*el.Key]; ok {
+				merged.AddVariable(*el.Key, v)
+				delete(values, *el.Key)
+				continue
 			}
-			fmt.Println(doc.String())
+		}
+
+		merged.Add(el)
+	}
+
+	for _, e := range entries {
+		if v, ok := values[e.Name]; ok {
+			merged.AddVariable(e.Name, v)
+			delete(values, e.Name)
+		}
+	}
+
+	return merged.String() + "\n", nil
+}
+
+// keyFailure records a secret osv get could not read.
+type keyFailure struct {
+	Key     string `json:"key"`
+	Error   string `json:"error"`
+	Missing bool   `json:"missing"`
+}
+
+func (f keyFailure) exitCode() int {
+	if f.Missing {
+		return exitMissing
+	}
+
+	return exitBackend
+}
+
+// reportFailures prints failures to stderr, either as error lines or as a
+// JSON document for scripts.
+func reportFailures(cmd *cobra.Command, failures []keyFailure, errorFormat string) {
+	if errorFormat == "json" {
+		b, _ := json.MarshalIndent(map[string][]keyFailure{"failures": failures}, "", "  ")
+		cmd.PrintErrln(string(b))
+		return
+	}
+
+	for _, failure := range failures {
+		Error(cmd, "getting secret %s failed: %v\n", failure.Key, failure.Error)
+	}
+}
+
+// parseOptionalKeys strips the optional marker, a trailing "?", from keys and
+// returns them, followed by the keys given by --optional, along with the set
+// of optional keys. A trailing "?" only marks a key as optional with literal
+// set or when the rest of the key has no glob characters; otherwise it is part
+// of the pattern. Keys given by --optional are never stripped.
+func parseOptionalKeys(keys, optionalKeys []string, literal bool) ([]string, map[string]bool) {
+	optional := map[string]bool{}
+	for _, key := range optionalKeys {
+		optional[key] = true
+	}
+
+	plain := make([]string, 0, len(keys)+len(optionalKeys))
+	for _, key := range keys {
+		trimmed, ok := strings.CutSuffix(key, "?")
+		if ok && trimmed != "" && (literal || !strings.ContainsAny(trimmed, "*?[{")) {
+			key = trimmed
+			optional[key] = true
+		}
+
+		plain = append(plain, key)
+	}
+
+	return append(plain, optionalKeys...), optional
+}
+
+// parseDefaults parses key=value pairs given by --default. Keys with a
+// default are appended to keys unless they were already requested.
+func parseDefaults(keys []string, pairs []string) ([]string, map[string]string, error) {
+	defaults := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, nil, fmt.Errorf("invalid --default %q (expected key=value)", pair)
+		}
+
+		if _, ok := defaults[key]; !ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+		defaults[key] = value
+	}
+
+	return keys, defaults, nil
+}
 
-		case "azure-devops", "ado":
-			for k, v := range values {
-				key := utils.ScreamingSnakeCase(k)
-				fmt.Printf("##vso[task.setvariable variable=%s;issecret=true]%s\n", key, v)
+// formatOptions collects the format specific flags of cmd.
+func formatOptions(cmd *cobra.Command) format.Options {
+	nest, _ := cmd.Flags().GetString("nest")
+	dir, _ := cmd.Flags().GetString("dir")
+	githubTarget, _ := cmd.Flags().GetString("github-target")
+	name, _ := cmd.Flags().GetString("name")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	secretType, _ := cmd.Flags().GetString("type")
+	labels, _ := cmd.Flags().GetStringSlice("labels")
+	stringData, _ := cmd.Flags().GetBool("string-data")
+	owner, _ := cmd.Flags().GetString("owner")
+	dropIn, _ := cmd.Flags().GetBool("systemd-dropin")
+	username, _ := cmd.Flags().GetString("username")
+	cost, _ := cmd.Flags().GetInt("cost")
+	rounds, _ := cmd.Flags().GetInt("rounds")
+	argonTime, _ := cmd.Flags().GetUint32("argon2-time")
+	argonMemory, _ := cmd.Flags().GetUint32("argon2-memory")
+	argonThreads, _ := cmd.Flags().GetUint8("argon2-threads")
+
+	return format.Options{
+		Nest:         nest,
+		Dir:          dir,
+		GitHubTarget: githubTarget,
+		K8s: format.K8sOptions{
+			Name:       name,
+			Namespace:  namespace,
+			Type:       secretType,
+			Labels:     labels,
+			StringData: stringData,
+		},
+		Systemd: format.SystemdOptions{
+			Owner:  owner,
+			DropIn: dropIn,
+		},
+		Hash: format.HashOptions{
+			User:    username,
+			Cost:    cost,
+			Rounds:  rounds,
+			Time:    argonTime,
+			Memory:  argonMemory,
+			Threads: argonThreads,
+		},
+		Warn: func(msg string) {
+			Warning(cmd, "%s\n", msg)
+		},
+	}
+}
+
+// expandKeys replaces every key containing glob characters with the matching
+// keys in kr, sorted by name. Patterns that match nothing are dropped and,
+// unless they are optional, returned as unmatched, just like missing literal
+// keys.
+func expandKeys(kr keyring.Keyring, keys []string, optional map[string]bool) ([]string, []string, error) {
+	var all, unmatched []string
+	expanded := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !strings.ContainsAny(key, "*?[{") {
+			expanded = append(expanded, key)
+			continue
+		}
+
+		matcher, err := glob.Compile(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern %s: %w", key, err)
+		}
+
+		if all == nil {
+			all, err = kr.Keys()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to list secrets: %w", err)
 			}
+			sort.Strings(all)
+		}
 
-		case "github":
-			for k, v := range values {
-				key := utils.ScreamingSnakeCase(k)
-				fmt.Printf("::add-mask::%s\n", v)
-				envPath := os.Getenv("GITHUB_ENV")
-				if envPath == "" {
-					Error(cmd, "GITHUB_ENV environment variable is not set\n")
-					osExit(1)
-				}
-				f, err := os.OpenFile(envPath, os.O_APPEND|os.O_WRONLY, 0600)
-				if err != nil {
-					Error(cmd, "opening GITHUB_ENV file failed: %v\n", err)
-					osExit(1)
-				}
-				defer f.Close()
-				if strings.ContainsAny(v, "\r\n") {
-					_, _ = f.WriteString(fmt.Sprintf("%s<<EOF\n%s\nEOF\n", key, v))
-				} else {
-					_, _ = f.WriteString(fmt.Sprintf("%s=%s\n", key, v))
-				}
+		matched := false
+		for _, k := range all {
+			if matcher.Match(k) {
+				expanded = append(expanded, k)
+				matched = true
 			}
+		}
+
+		if !matched && !optional[key] {
+			unmatched = append(unmatched, key)
+		}
+	}
+
+	return expanded, unmatched, nil
+}
+
+// nameOptions controls how secret keys are turned into variable names.
+type nameOptions struct {
+	Prefix      string
+	StripPrefix string
+	Case        string
+}
+
+func newNameOptions(cmd *cobra.Command) (nameOptions, error) {
+	prefix, _ := cmd.Flags().GetString("prefix")
+	stripPrefix, _ := cmd.Flags().GetString("strip-prefix")
+	nameCase, _ := cmd.Flags().GetString("case")
 
-		default:
-			for _, v := range values {
-				fmt.Println(v)
+	switch nameCase {
+	case "screaming-snake", "snake", "kebab", "none":
+	default:
+		return nameOptions{}, fmt.Errorf("invalid --case %q (expected screaming-snake, snake, kebab or none)", nameCase)
+	}
+
+	return nameOptions{Prefix: prefix, StripPrefix: stripPrefix, Case: nameCase}, nil
+}
+
+// name returns the variable name for key.
+func (o nameOptions) name(key string) string {
+	name := strings.TrimPrefix(key, o.StripPrefix)
+
+	switch o.Case {
+	case "snake":
+		name = utils.SnakeCase(name)
+	case "kebab":
+		name = utils.KebabCase(name)
+	case "none":
+	default:
+		name = utils.ScreamingSnakeCase(name)
+	}
+
+	return o.Prefix + name
+}
+
+// resolveNames pairs each requested key with its variable name, in request
+// order. Aliases of the form NAME=key come after the plain keys and use NAME
+// verbatim. Repeated keys are dropped, and two keys mapping to the same name is
+// an error.
+func resolveNames(keys []string, aliases []string, naming nameOptions) ([]format.Entry, error) {
+	entries := make([]format.Entry, 0, len(keys)+len(aliases))
+	owners := map[string]string{}
+
+	add := func(key, name string) error {
+		if owner, ok := owners[name]; ok {
+			if owner == key {
+				return nil
 			}
+
+			return fmt.Errorf("secrets %s and %s both map to the name %s", owner, key, name)
 		}
-	},
+
+		owners[name] = key
+		entries = append(entries, format.Entry{Key: key, Name: name})
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := add(key, naming.name(key)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, alias := range aliases {
+		name, key, ok := strings.Cut(alias, "=")
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("invalid --as %q (expected NAME=secret-key)", alias)
+		}
+
+		if err := add(key, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
 }
 
 func init() {
@@ -161,6 +675,44 @@ func init() {
 	service := os.Getenv("OSV_SERVICE")
 	getCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
 	getCmd.Flags().StringSliceP("key", "k", []string{}, "Name of secret(s) to get (can be specified multiple times)")
-	getCmd.Flags().StringP("format", "f", "text", "Output format (text, json, sh, bash, zsh, powershell, pwsh, dotenv)")
+	getCmd.Flags().StringP("format", "f", "text", "Output format (run 'osv formats' to list them)")
 	getCmd.Flags().BoolP("clip", "c", false, "Copy the first secret to clipboard instead of printing")
+	getCmd.Flags().StringArray("as", []string{}, "Export a secret under an explicit name, as NAME=secret-key (can be specified multiple times)")
+	getCmd.Flags().String("encoding", "", "Encode secret values before output: raw, base64 or hex")
+	getCmd.Flags().StringP("out", "o", "", "Write the output to this file atomically instead of stdout")
+	getCmd.Flags().String("mode", "0600", "Permissions of the file written by --out")
+	getCmd.Flags().Bool("append", false, "Append to the file given by --out instead of replacing it")
+	getCmd.Flags().Bool("merge", false, "Merge into the dotenv file given by --out, updating existing variables")
+	getCmd.Flags().StringSlice("optional", []string{}, "Name of secret(s) to get that may be missing (can be specified multiple times)")
+	getCmd.Flags().StringArray("default", []string{}, "Value to use for a missing secret, as key=value (can be specified multiple times)")
+	getCmd.Flags().Bool("continue-on-error", false, "Output the secrets that could be read and report the others on stderr")
+	getCmd.Flags().String("error-format", "text", "Format of failures reported with --continue-on-error (text, json)")
+	getCmd.Flags().Bool("literal", false, "Treat keys as literal names instead of glob patterns")
+	getCmd.Flags().Bool("sort", false, "Sort output by secret name instead of request order")
+	getCmd.Flags().String("prefix", "", "Prefix added to every generated variable name")
+	getCmd.Flags().String("strip-prefix", "", "Prefix removed from secret names before generating variable names")
+	getCmd.Flags().String("case", "screaming-snake", "Case of generated variable names (screaming-snake, snake, kebab, none)")
+	getCmd.Flags().String("nest", "", "Split secret names on this separator into nested objects (json, yaml, toml, tfvars formats)")
+	getCmd.Flags().Lookup("nest").NoOptDefVal = "."
+	getCmd.Flags().String("github-target", "env", "GitHub Actions file to write to: env, output or both (github format)")
+	getCmd.Flags().String("dir", "", "Directory to write one file per secret into (compose-secrets, systemd-creds formats)")
+
+	// systemd credential options
+	getCmd.Flags().String("owner", "", "Owner of credential files as user[:group] (systemd-creds format)")
+	getCmd.Flags().Bool("systemd-dropin", false, "Print a unit drop-in with SetCredential= lines instead of writing files (systemd-creds format)")
+
+	// Kubernetes secret options
+	getCmd.Flags().String("name", "", "Name of the Kubernetes secret (k8s-secret format)")
+	getCmd.Flags().String("namespace", "", "Namespace of the Kubernetes secret (k8s-secret format)")
+	getCmd.Flags().String("type", "Opaque", "Type of the Kubernetes secret (k8s-secret format)")
+	getCmd.Flags().StringSlice("labels", []string{}, "Labels of the Kubernetes secret as key=value (k8s-secret format)")
+	getCmd.Flags().Bool("string-data", false, "Write plain stringData instead of base64 data (k8s-secret format)")
+
+	// Password hash options
+	getCmd.Flags().String("username", "", "User name written before each hash as user:hash (htpasswd, bcrypt, argon2id, sha512-crypt formats; htpasswd defaults to the secret name)")
+	getCmd.Flags().Int("cost", format.DefaultBcryptCost, "bcrypt cost (htpasswd, bcrypt formats)")
+	getCmd.Flags().Int("rounds", 0, "Number of rounds (sha512-crypt format, default 5000)")
+	getCmd.Flags().Uint32("argon2-time", format.DefaultArgon2Time, "Number of passes (argon2id format)")
+	getCmd.Flags().Uint32("argon2-memory", format.DefaultArgon2Memory, "Memory in KiB (argon2id format)")
+	getCmd.Flags().Uint8("argon2-threads", format.DefaultArgon2Threads, "Degree of parallelism (argon2id format)")
 }
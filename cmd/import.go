@@ -0,0 +1,535 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/keyring"
+	"github.com/frostyeti/go/dotenv"
+	"github.com/gobwas/glob"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import secrets from a dotenv, JSON, YAML or Kubernetes Secret file",
+	Long: `Import many secrets at once from a file.
+
+Supported input formats, detected from the file extension unless --input-format
+is given:
+  dotenv       NAME=value lines (.env)
+  json         An object of names to values; nested objects are flattened
+               with dots (.json)
+  yaml         A mapping of names to values, flattened like json (.yaml, .yml)
+  k8s-secret   A Kubernetes Secret manifest; base64 data and plain stringData
+               are both imported. YAML files with kind: Secret are detected
+               automatically.
+
+Entry names are filtered with --include and --exclude glob patterns and turned
+into keys with --strip-prefix, --case and --prefix, in that order.
+
+Before anything is written osv prints a preview of the changes. Existing
+secrets are not touched unless a conflict policy is given:
+  --skip-existing   Keep existing secrets and import only new ones
+  --overwrite       Replace existing secrets
+  --interactive     Ask for each existing secret
+
+Values are never printed. Use --dry-run to only show the preview.
+
+Examples:
+  # Preview what would be imported from a .env file
+  osv import .env --dry-run
+
+  # Turn DB_PASSWORD into app-db-password, keeping existing secrets
+  osv import .env --case kebab --prefix app- --skip-existing
+
+  # Import only the database entries of a Kubernetes secret
+  kubectl get secret db -o yaml | osv import - --input-format k8s-secret --include 'db*' --overwrite`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFormat, _ := cmd.Flags().GetString("input-format")
+		includes, _ := cmd.Flags().GetStringSlice("include")
+		excludes, _ := cmd.Flags().GetStringSlice("exclude")
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		policies := 0
+		for _, set := range []bool{skipExisting, overwrite, interactive} {
+			if set {
+				policies++
+			}
+		}
+		if policies > 1 {
+			Error(cmd, "options --skip-existing, --overwrite and --interactive are mutually exclusive\n")
+			osExit(1)
+		}
+
+		if interactive && !dryRun && !stdinIsTerminal() {
+			Error(cmd, "refusing to ask for confirmation because stdin is not a terminal, use --skip-existing or --overwrite\n")
+			osExit(1)
+		}
+
+		naming, err := newNameOptions(cmd)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		include, err := compileGlobs(includes)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		exclude, err := compileGlobs(excludes)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		var src []byte
+		if args[0] == "-" {
+			src, err = io.ReadAll(os.Stdin)
+		} else {
+			src, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			Error(cmd, "reading %s failed: %v\n", args[0], err)
+			osExit(1)
+		}
+
+		if inputFormat == "" {
+			inputFormat = detectImportFormat(args[0], src)
+		}
+
+		entries, err := parseImport(inputFormat, src)
+		if err != nil {
+			Error(cmd, "parsing %s as %s failed: %v\n", args[0], inputFormat, err)
+			osExit(1)
+		}
+
+		var plan []importEntry
+		keys := map[string]string{}
+		for _, e := range entries {
+			if (len(include) > 0 && !matchesAny(include, e.Name)) || matchesAny(exclude, e.Name) {
+				continue
+			}
+
+			e.Key = importKey(naming, e.Name)
+			if e.Key == "" {
+				Error(cmd, "entry %s maps to an empty key\n", e.Name)
+				osExit(1)
+			}
+
+			if other, ok := keys[e.Key]; ok {
+				Error(cmd, "entries %s and %s both map to the key %s\n", other, e.Name, e.Key)
+				osExit(1)
+			}
+
+			keys[e.Key] = e.Name
+			plan = append(plan, e)
+		}
+
+		if len(plan) == 0 {
+			Warning(cmd, "no entries to import from %s\n", args[0])
+			return
+		}
+
+		kr, err := openKeyring(cmd)
+		if err != nil {
+			Error(cmd, "opening keyring failed: %v\n", err)
+			osExit(exitBackend)
+		}
+
+		existing := map[string]bool{}
+		if names, err := kr.Keys(); err == nil {
+			for _, name := range names {
+				existing[name] = true
+			}
+		} else {
+			Error(cmd, "listing secrets failed: %v\n", err)
+			osExit(exitBackend)
+		}
+
+		conflicts := 0
+		for i := range plan {
+			e := &plan[i]
+			switch {
+			case !existing[e.Key]:
+				e.Action = "create"
+			case overwrite:
+				e.Action = "overwrite"
+			case skipExisting:
+				e.Action = "skip"
+			case interactive:
+				e.Action = "ask"
+			default:
+				e.Action = "conflict"
+				conflicts++
+			}
+		}
+
+		printImportPlan(cmd, plan)
+
+		if conflicts > 0 {
+			Error(cmd, "%d secret(s) already exist, use --skip-existing, --overwrite or --interactive\n", conflicts)
+			osExit(1)
+		}
+
+		if dryRun {
+			return
+		}
+
+		answers := bufio.NewReader(os.Stdin)
+		imported, skipped := 0, 0
+		for _, e := range plan {
+			if e.Action == "ask" {
+				ok, err := confirm(answers, fmt.Sprintf("Overwrite existing secret %s?", e.Key))
+				if err != nil {
+					Error(cmd, "%v\n", err)
+					osExit(1)
+				}
+
+				if !ok {
+					e.Action = "skip"
+				}
+			}
+
+			if e.Action == "skip" {
+				skipped++
+				continue
+			}
+
+			if err := kr.Set(keyring.Item{Key: e.Key, Data: e.Value}); err != nil {
+				Error(cmd, "setting secret %s failed: %v\n", e.Key, err)
+				osExit(1)
+			}
+
+			imported++
+		}
+
+		Ok(cmd, "imported %d secret(s), skipped %d\n", imported, skipped)
+	},
+}
+
+// importEntry is one entry read from an import file and what will happen to
+// it.
+type importEntry struct {
+	// Name is the entry's name in the file and Key the secret it is
+	// imported as.
+	Name   string
+	Key    string
+	Value  []byte
+	Action string
+}
+
+// detectImportFormat guesses the format of an import file from its extension
+// and, for YAML, whether it is a Kubernetes Secret manifest.
+func detectImportFormat(path string, src []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		var manifest struct {
+			Kind string `yaml:"kind"`
+		}
+		if yaml.Unmarshal(src, &manifest) == nil && manifest.Kind == "Secret" {
+			return "k8s-secret"
+		}
+
+		return "yaml"
+	default:
+		return "dotenv"
+	}
+}
+
+// parseImport reads the entries of src in the given format, in file order
+// for dotenv and sorted by name for the other formats.
+func parseImport(inputFormat string, src []byte) ([]importEntry, error) {
+	switch inputFormat {
+	case "dotenv", "env":
+		doc, err := dotenv.Parse(string(src))
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []importEntry
+		for _, name := range doc.Keys() {
+			value, _ := doc.Get(name)
+			entries = append(entries, importEntry{Name: name, Value: []byte(value)})
+		}
+
+		return entries, nil
+	case "json":
+		var data map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(src))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return nil, err
+		}
+
+		return flattenImport(data)
+	case "yaml", "yml":
+		var data map[string]interface{}
+		if err := yaml.Unmarshal(src, &data); err != nil {
+			return nil, err
+		}
+
+		return flattenImport(data)
+	case "k8s-secret":
+		var secret struct {
+			Kind       string            `yaml:"kind"`
+			Data       map[string]string `yaml:"data"`
+			StringData map[string]string `yaml:"stringData"`
+		}
+		if err := yaml.Unmarshal(src, &secret); err != nil {
+			return nil, err
+		}
+
+		if secret.Kind != "Secret" {
+			return nil, fmt.Errorf("expected kind Secret, got %q", secret.Kind)
+		}
+
+		values := map[string][]byte{}
+		for name, encoded := range secret.Data {
+			value, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("data.%s is not valid base64: %w", name, err)
+			}
+			values[name] = value
+		}
+
+		// stringData takes precedence, as it does when applied to a cluster.
+		for name, value := range secret.StringData {
+			values[name] = []byte(value)
+		}
+
+		return sortedImport(values), nil
+	default:
+		return nil, fmt.Errorf("unknown input format %q (dotenv, json, yaml, k8s-secret)", inputFormat)
+	}
+}
+
+// importKey returns the key for an imported entry. Prefixes are handled like
+// nameOptions.name, but the case is converted by importCase so that names
+// such as DB_PASSWORD or APIToken keep their word boundaries.
+func importKey(o nameOptions, name string) string {
+	name = strings.TrimPrefix(name, o.StripPrefix)
+	if o.Case != "none" {
+		name = importCase(name, o.Case)
+	}
+
+	return o.Prefix + name
+}
+
+// importCase splits name into words on characters that are not ASCII letters
+// or digits and on case changes, keeping acronyms together, and joins them in
+// nameCase.
+func importCase(name, nameCase string) string {
+	isUpper := func(r rune) bool { return r >= 'A' && r <= 'Z' }
+	isLower := func(r rune) bool { return r >= 'a' && r <= 'z' }
+	isDigit := func(r rune) bool { return r >= '0' && r <= '9' }
+
+	runes := []rune(name)
+	var words []string
+	var word strings.Builder
+	for i, r := range runes {
+		if !isUpper(r) && !isLower(r) && !isDigit(r) {
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		}
+
+		if isUpper(r) && i > 0 && word.Len() > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && isLower(runes[i+1])
+			if isLower(prev) || isDigit(prev) || (isUpper(prev) && nextLower) {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		}
+
+		word.WriteRune(r)
+	}
+
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	switch nameCase {
+	case "kebab":
+		return strings.ToLower(strings.Join(words, "-"))
+	case "snake":
+		return strings.ToLower(strings.Join(words, "_"))
+	default:
+		return strings.ToUpper(strings.Join(words, "_"))
+	}
+}
+
+// flattenImport turns nested objects into entries whose names are joined
+// with dots. Strings, numbers and booleans are imported as their text; lists,
+// maps with non-string keys and other values such as YAML timestamps are
+// rejected.
+func flattenImport(data map[string]interface{}) ([]importEntry, error) {
+	values := map[string][]byte{}
+	var walk func(prefix string, v interface{}) error
+	walk = func(prefix string, v interface{}) error {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				name := k
+				if prefix != "" {
+					name = prefix + "." + k
+				}
+
+				if err := walk(name, child); err != nil {
+					return err
+				}
+			}
+		case string:
+			values[prefix] = []byte(v)
+		case nil:
+			values[prefix] = nil
+		case map[interface{}]interface{}:
+			return fmt.Errorf("%s has a non-string key, only string keys can be imported", prefix)
+		case []interface{}:
+			return fmt.Errorf("%s is a list, only objects and scalars can be imported", prefix)
+		case json.Number:
+			values[prefix] = []byte(v.String())
+		case bool, int, int64, uint64:
+			values[prefix] = []byte(fmt.Sprint(v))
+		case float64:
+			values[prefix] = []byte(strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return fmt.Errorf("%s has an unsupported %T value, quote it to import it as text", prefix, v)
+		}
+
+		return nil
+	}
+
+	if err := walk("", data); err != nil {
+		return nil, err
+	}
+
+	return sortedImport(values), nil
+}
+
+func sortedImport(values map[string][]byte) []importEntry {
+	entries := make([]importEntry, 0, len(values))
+	for name, value := range values {
+		entries = append(entries, importEntry{Name: name, Value: value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
+
+func compileGlobs(patterns []string) ([]glob.Glob, error) {
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+
+		globs = append(globs, g)
+	}
+
+	return globs, nil
+}
+
+func matchesAny(globs []glob.Glob, name string) bool {
+	for _, g := range globs {
+		if g.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// printImportPlan prints one line per entry with the action taken for it.
+func printImportPlan(cmd *cobra.Command, plan []importEntry) {
+	symbols := map[string]string{
+		"create":    "+",
+		"overwrite": "~",
+		"skip":      "=",
+		"ask":       "?",
+		"conflict":  "!",
+	}
+
+	for _, e := range plan {
+		line := fmt.Sprintf("%s %s", symbols[e.Action], e.Key)
+		if e.Key != e.Name {
+			line += " (from " + e.Name + ")"
+		}
+
+		switch e.Action {
+		case "overwrite":
+			line += " [overwrite]"
+		case "skip":
+			line += " [exists, skipped]"
+		case "ask":
+			line += " [exists, will ask]"
+		case "conflict":
+			line += " [exists]"
+		}
+
+		cmd.PrintErrln(line)
+	}
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from r.
+func confirm(r *bufio.Reader, question string) (bool, error) {
+	_, _ = fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || answer == "") {
+		return false, fmt.Errorf("reading answer failed: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	service := os.Getenv("OSV_SERVICE")
+	importCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
+	importCmd.Flags().StringP("input-format", "f", "", "Format of the file: dotenv, json, yaml or k8s-secret (default: detected from the extension)")
+	importCmd.Flags().StringSlice("include", []string{}, "Only import entries whose name matches this glob (can be specified multiple times)")
+	importCmd.Flags().StringSlice("exclude", []string{}, "Skip entries whose name matches this glob (can be specified multiple times)")
+	importCmd.Flags().String("prefix", "", "Prefix added to every imported key")
+	importCmd.Flags().String("strip-prefix", "", "Prefix removed from entry names before generating keys")
+	importCmd.Flags().String("case", "none", "Case of generated keys (none, kebab, snake, screaming-snake)")
+	importCmd.Flags().Bool("skip-existing", false, "Keep secrets that already exist")
+	importCmd.Flags().Bool("overwrite", false, "Replace secrets that already exist")
+	importCmd.Flags().BoolP("interactive", "i", false, "Ask before replacing each secret that already exists")
+	importCmd.Flags().Bool("dry-run", false, "Only print the changes that would be made")
+	importCmd.MarkFlagsMutuallyExclusive("skip-existing", "overwrite", "interactive")
+}
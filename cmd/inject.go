@@ -0,0 +1,175 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/99designs/keyring"
+	"github.com/frostyeti/osv/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// injectCmd represents the inject command
+var injectCmd = &cobra.Command{
+	Use:   "inject [-i <input>] [-o <output>]",
+	Short: "Replace osv:// secret references in a file with their values",
+	Long: `Replace every osv:// secret reference in a file with the secret's value.
+
+A reference names the service and the key of a secret, and optionally the
+keyring backend to read it from:
+
+  osv://<service>/<key>
+  osv://<backend>@<service>/<key>
+
+where <backend> is one of keychain, wincred or secret-service. References
+always use the service they name, never the --service flag or the configured
+default, so files can mix secrets from several services.
+
+When --output is given the result is written atomically with 0600
+permissions, otherwise it is printed. Without --input the file is read from
+stdin.
+
+Examples:
+  # config.yml.tpl contains "token: osv://my-service/api-token"
+  osv inject -i config.yml.tpl -o config.yml
+
+  # Use the macOS keychain explicitly
+  echo 'osv://keychain@my-service/api-token' | osv inject`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		output, _ := cmd.Flags().GetString("output")
+
+		var src []byte
+		var err error
+		if input == "" || input == "-" {
+			src, err = io.ReadAll(os.Stdin)
+		} else {
+			src, err = os.ReadFile(input)
+		}
+		if err != nil {
+			Error(cmd, "reading %s failed: %v\n", input, err)
+			osExit(1)
+		}
+
+		data, err := newRefResolver().replace(string(src))
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		if output == "" {
+			_, _ = os.Stdout.WriteString(data)
+			return
+		}
+
+		if err := utils.WriteFileAtomic(output, []byte(data), 0600); err != nil {
+			Error(cmd, "writing %s failed: %v\n", output, err)
+			osExit(1)
+		}
+
+		Ok(cmd, "injected secrets into %s\n", output)
+	},
+}
+
+// refPattern matches osv://[backend@]service/key secret references. Keys may
+// contain slashes but do not end in a dot or slash, so references at the end
+// of a sentence or path stop where the key does.
+var refPattern = regexp.MustCompile(`osv://(?:([a-z-]+)@)?([A-Za-z0-9._-]+)/([A-Za-z0-9._~/-]*[A-Za-z0-9_~-])`)
+
+// refBackends maps the backend names accepted in references.
+var refBackends = map[string]keyring.BackendType{
+	"keychain":       keyring.KeychainBackend,
+	"wincred":        keyring.WinCredBackend,
+	"secret-service": keyring.SecretServiceBackend,
+}
+
+// refResolver resolves secret references, opening each keyring and reading
+// each secret at most once.
+type refResolver struct {
+	keyrings map[string]keyring.Keyring
+	values   map[string]string
+}
+
+func newRefResolver() *refResolver {
+	return &refResolver{
+		keyrings: map[string]keyring.Keyring{},
+		values:   map[string]string{},
+	}
+}
+
+// replace returns s with every secret reference replaced by its value.
+func (r *refResolver) replace(s string) (string, error) {
+	var firstErr error
+	result := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if firstErr != nil {
+			return ref
+		}
+
+		v, err := r.resolve(ref)
+		if err != nil {
+			firstErr = err
+			return ref
+		}
+
+		return v
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	return result, nil
+}
+
+// resolve returns the value of a single reference.
+func (r *refResolver) resolve(ref string) (string, error) {
+	if v, ok := r.values[ref]; ok {
+		return v, nil
+	}
+
+	m := refPattern.FindStringSubmatch(ref)
+	if m == nil || m[0] != ref {
+		return "", fmt.Errorf("invalid secret reference %s", ref)
+	}
+
+	backendName, service, key := m[1], m[2], m[3]
+	backend := keyring.InvalidBackend
+	if backendName != "" {
+		b, ok := refBackends[backendName]
+		if !ok {
+			return "", fmt.Errorf("unknown backend %s in secret reference %s", backendName, ref)
+		}
+		backend = b
+	}
+
+	krKey := backendName + "@" + service
+	kr, ok := r.keyrings[krKey]
+	if !ok {
+		var err error
+		kr, err = ServiceKeyringProvider(service, backend)
+		if err != nil {
+			return "", fmt.Errorf("opening keyring for %s failed: %w", ref, err)
+		}
+		r.keyrings[krKey] = kr
+	}
+
+	item, err := kr.Get(key)
+	if err != nil {
+		return "", fmt.Errorf("getting secret %s failed: %w", ref, err)
+	}
+
+	r.values[ref] = string(item.Data)
+	return r.values[ref], nil
+}
+
+func init() {
+	rootCmd.AddCommand(injectCmd)
+	injectCmd.Flags().StringP("input", "i", "", "File to read references from (default: stdin)")
+	injectCmd.Flags().StringP("output", "o", "", "Write the result to this file instead of stdout")
+}
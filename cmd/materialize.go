@@ -0,0 +1,250 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/99designs/keyring"
+	"github.com/frostyeti/osv/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// materializeCmd represents the materialize command
+var materializeCmd = &cobra.Command{
+	Use:   "materialize [--dir <dir>] <key>... [-- <command> [args...]]",
+	Short: "Write secrets to temporary files for tools that only read files",
+	Long: `Write each secret to its own file for tools that only read credentials
+from files, such as kubeconfigs, service account keys or TLS keys.
+
+Every secret is written with 0600 permissions to a file named after its key
+in --dir, which must be on a memory-backed file system (tmpfs) unless
+--allow-disk is set. Without --dir a new directory is created under
+$XDG_RUNTIME_DIR/osv.
+
+When a command is given after --, it runs with the directory exported in the
+variable named by --env (OSV_SECRETS_DIR by default). The files are
+overwritten and removed when the command exits or osv receives a signal, and
+osv exits with the command's exit code.
+
+Without a command, osv prints the directory, holds the files until --ttl
+expires or it is interrupted, and then removes them.
+
+Examples:
+  # Give kubectl a kubeconfig that only exists while it runs
+  osv materialize kubeconfig -- sh -c 'kubectl --kubeconfig "$OSV_SECRETS_DIR/kubeconfig" get pods'
+
+  # Use an explicit directory
+  osv materialize --dir /run/user/$UID/osv/app tls-key tls-cert -- ./server
+
+  # Keep the files around for ten minutes
+  osv materialize gcp-sa-key --ttl 10m &`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keys, _ := cmd.Flags().GetStringSlice("key")
+		dir, _ := cmd.Flags().GetString("dir")
+		envName, _ := cmd.Flags().GetString("env")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		allowDisk, _ := cmd.Flags().GetBool("allow-disk")
+
+		var command []string
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			keys = append(keys, args[:dash]...)
+			command = args[dash:]
+		} else {
+			keys = append(keys, args...)
+		}
+
+		if len(keys) == 0 {
+			Error(cmd, "at least one --key must be provided\n")
+			osExit(1)
+		}
+
+		for _, key := range keys {
+			if !validFileName(key) {
+				Error(cmd, "secret %s cannot be used as a file name\n", key)
+				osExit(1)
+			}
+		}
+
+		if len(command) == 0 && ttl <= 0 {
+			Error(cmd, "--ttl is required when no command is given\n")
+			osExit(1)
+		}
+
+		kr, err := openKeyring(cmd)
+		if err != nil {
+			Error(cmd, "opening keyring failed: %v\n", err)
+			osExit(exitBackend)
+		}
+
+		values := make([][]byte, len(keys))
+		for i, key := range keys {
+			item, err := kr.Get(key)
+			if err != nil {
+				Error(cmd, "getting secret %s failed: %v\n", key, err)
+				if errors.Is(err, keyring.ErrKeyNotFound) {
+					osExit(exitMissing)
+				}
+				osExit(exitBackend)
+			}
+
+			values[i] = item.Data
+		}
+
+		m, err := newMaterialized(dir)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		if !allowDisk {
+			ok, err := memoryBacked(m.dir)
+			if err != nil || !ok {
+				m.remove(cmd)
+				Error(cmd, "%s is not on a memory-backed file system, use a tmpfs directory or --allow-disk\n", m.dir)
+				osExit(1)
+			}
+		}
+
+		for i, key := range keys {
+			if err := m.write(key, values[i]); err != nil {
+				m.remove(cmd)
+				Error(cmd, "writing secret %s failed: %v\n", key, err)
+				osExit(1)
+			}
+		}
+
+		if len(command) > 0 {
+			env := append(os.Environ(), envName+"="+m.dir)
+			code := runChild(cmd, command, env)
+			m.remove(cmd)
+			osExit(code)
+			return
+		}
+
+		fmt.Fprintln(os.Stdout, m.dir)
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, holdSignals...)
+		defer signal.Stop(signals)
+
+		timer := time.NewTimer(ttl)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-signals:
+		}
+
+		m.remove(cmd)
+	},
+}
+
+// holdSignals end the wait for --ttl early. Signals such as SIGWINCH that do
+// not ask osv to stop are left out.
+var holdSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// materialized tracks the secret files written by osv materialize so they
+// can be removed again.
+type materialized struct {
+	dir     string
+	created bool
+	files   []string
+}
+
+// newMaterialized prepares dir for secret files, creating it with 0700
+// permissions if needed. An empty dir selects a fresh directory under
+// $XDG_RUNTIME_DIR/osv.
+func newMaterialized(dir string) (*materialized, error) {
+	if dir == "" {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			return nil, errors.New("XDG_RUNTIME_DIR is not set, use --dir to choose a directory")
+		}
+
+		base := filepath.Join(runtimeDir, "osv")
+		if err := os.MkdirAll(base, 0700); err != nil {
+			return nil, fmt.Errorf("creating %s failed: %w", base, err)
+		}
+
+		d, err := os.MkdirTemp(base, "materialize-")
+		if err != nil {
+			return nil, fmt.Errorf("creating directory in %s failed: %w", base, err)
+		}
+
+		return &materialized{dir: d, created: true}, nil
+	}
+
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+
+		return &materialized{dir: dir}, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating %s failed: %w", dir, err)
+	}
+
+	return &materialized{dir: dir, created: true}, nil
+}
+
+// write stores data in a 0600 file named name.
+func (m *materialized) write(name string, data []byte) error {
+	path := filepath.Join(m.dir, name)
+	if err := utils.WriteSecretFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	m.files = append(m.files, path)
+	return nil
+}
+
+// remove shreds every written file and removes the directory if osv created
+// it. Failures are reported as warnings so that as much as possible is
+// cleaned up.
+func (m *materialized) remove(cmd *cobra.Command) {
+	for _, path := range m.files {
+		if err := utils.ShredFile(path); err != nil && !os.IsNotExist(err) {
+			Warning(cmd, "removing %s failed: %v\n", path, err)
+		}
+	}
+
+	m.files = nil
+	if m.created {
+		if err := os.Remove(m.dir); err != nil && !os.IsNotExist(err) {
+			Warning(cmd, "removing %s failed: %v\n", m.dir, err)
+		}
+	}
+}
+
+// validFileName reports whether a secret key can be used as a file name
+// inside the materialize directory without escaping it.
+func validFileName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`+"\x00")
+}
+
+func init() {
+	rootCmd.AddCommand(materializeCmd)
+	service := os.Getenv("OSV_SERVICE")
+	materializeCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
+	materializeCmd.Flags().StringSliceP("key", "k", []string{}, "Name of secret(s) to write (can be specified multiple times)")
+	materializeCmd.Flags().StringP("dir", "d", "", "Directory to write the secret files to (default: a new directory under $XDG_RUNTIME_DIR/osv)")
+	materializeCmd.Flags().String("env", "OSV_SECRETS_DIR", "Variable the directory is exported in for the command")
+	materializeCmd.Flags().Duration("ttl", 0, "How long to keep the files when no command is given")
+	materializeCmd.Flags().Bool("allow-disk", false, "Allow writing to a directory that is not memory-backed")
+}
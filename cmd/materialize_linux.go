@@ -0,0 +1,20 @@
+package cmd
+
+import "syscall"
+
+// Magic numbers of the memory-backed file systems reported by statfs(2).
+const (
+	tmpfsMagic = 0x01021994
+	ramfsMagic = 0x858458f6
+)
+
+// memoryBacked reports whether dir lives on tmpfs or ramfs, so files written
+// to it never reach a disk.
+func memoryBacked(dir string) (bool, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return false, err
+	}
+
+	return st.Type == tmpfsMagic || st.Type == ramfsMagic, nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package cmd
+
+// memoryBacked reports whether dir lives on a memory-backed file system. Only
+// Linux can tell, so elsewhere every directory is treated as disk-backed.
+func memoryBacked(dir string) (bool, error) {
+	return false, nil
+}
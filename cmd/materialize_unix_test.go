@@ -0,0 +1,38 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/99designs/keyring"
+)
+
+func TestMaterializeHoldIgnoresSIGWINCH(t *testing.T) {
+	mk, _, _ := setupTest(t)
+	_ = mk.Set(keyring.Item{Key: "tls-key", Data: []byte("KEY")})
+
+	dir := filepath.Join(t.TempDir(), "secrets")
+	file := filepath.Join(dir, "tls-key")
+
+	existed := make(chan bool, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+		time.Sleep(100 * time.Millisecond)
+		_, err := os.Stat(file)
+		existed <- err == nil
+	}()
+
+	_, errOut, _ := executeCommand("materialize", "--dir", dir, "--allow-disk", "--ttl", "500ms", "tls-key")
+	if !<-existed {
+		t.Errorf("Expected the secret file to survive SIGWINCH during the hold, err: %s", errOut)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("Expected the secret file to be removed after the ttl, got: %v", err)
+	}
+}
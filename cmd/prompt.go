@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"golang.org/x/term"
+)
+
+// stdinIsTerminal reports whether stdin is an interactive terminal. Tests
+// replace it.
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// readPassword reads a line from the terminal without echoing it. Tests
+// replace it.
+var readPassword = defaultReadPassword
+
+// defaultReadPassword reads a line from stdin with echo disabled, restoring
+// the terminal when interrupted so the shell is not left without echo.
+func defaultReadPassword() ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.GetState(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	go func() {
+		if _, ok := <-signals; ok {
+			_ = term.Restore(fd, state)
+			_, _ = os.Stderr.WriteString("\n")
+			osExit(130)
+		}
+	}()
+
+	return term.ReadPassword(fd)
+}
+
+// promptSecret asks for the value of key twice without echo and returns it
+// once both entries match.
+func promptSecret(key string) (string, error) {
+	if !stdinIsTerminal() {
+		return "", errors.New("refusing to prompt because stdin is not a terminal, use --value, --file, --var, --stdin, --generate or --exec")
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Enter value for %s: ", key)
+	first, err := readPassword()
+	_, _ = os.Stderr.WriteString("\n")
+	if err != nil {
+		return "", fmt.Errorf("reading value failed: %w", err)
+	}
+
+	if len(first) == 0 {
+		return "", errors.New("the value must not be empty")
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Confirm value for %s: ", key)
+	second, err := readPassword()
+	_, _ = os.Stderr.WriteString("\n")
+	if err != nil {
+		return "", fmt.Errorf("reading value failed: %w", err)
+	}
+
+	if string(first) != string(second) {
+		return "", errors.New("the values do not match")
+	}
+
+	return string(first), nil
+}
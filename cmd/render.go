@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/99designs/keyring"
+	"github.com/frostyeti/osv/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render <template>",
+	Short: "Render a Go template that pulls secrets from the keyring",
+	Long: `Render a Go text/template file, resolving secrets from the OS keyring.
+
+The template can use these functions in addition to the text/template builtins:
+  secret "key"   The value of a secret in the keyring
+  env "NAME"     The value of an environment variable
+  b64enc         Base64 encode a value:  {{ secret "key" | b64enc }}
+  b64dec         Base64 decode a value
+  json           Quote a value as a JSON string
+  trim           Remove leading and trailing whitespace
+
+Secrets that do not exist render as empty strings with a warning, unless
+--strict is set, in which case rendering fails. When --output is given the
+result is written atomically with 0600 permissions, otherwise it is printed.
+Use - as the template to read it from stdin.
+
+Examples:
+  # Render a config file
+  osv render appsettings.json.tmpl -o appsettings.json
+
+  # Fail if any referenced secret is missing
+  osv render nginx.conf.tmpl -o nginx.conf --strict`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		var src []byte
+		var err error
+		if args[0] == "-" {
+			src, err = io.ReadAll(os.Stdin)
+		} else {
+			src, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			Error(cmd, "reading template %s failed: %v\n", args[0], err)
+			osExit(1)
+		}
+
+		kr, err := openKeyring(cmd)
+		if err != nil {
+			Error(cmd, "opening keyring failed: %v\n", err)
+			osExit(1)
+		}
+
+		data, err := renderTemplate(cmd, args[0], string(src), kr, strict)
+		if err != nil {
+			Error(cmd, "rendering template %s failed: %v\n", args[0], err)
+			osExit(1)
+		}
+
+		if output == "" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+
+		if err := utils.WriteFileAtomic(output, data, 0600); err != nil {
+			Error(cmd, "writing %s failed: %v\n", output, err)
+			osExit(1)
+		}
+
+		Ok(cmd, "rendered %s to %s\n", args[0], output)
+	},
+}
+
+// renderTemplate executes src with the render functions, fetching each secret
+// from kr at most once.
+func renderTemplate(cmd *cobra.Command, name, src string, kr keyring.Keyring, strict bool) ([]byte, error) {
+	cache := map[string]string{}
+
+	funcs := template.FuncMap{
+		"secret": func(key string) (string, error) {
+			if v, ok := cache[key]; ok {
+				return v, nil
+			}
+
+			item, err := kr.Get(key)
+			if err != nil {
+				if strict || !errors.Is(err, keyring.ErrKeyNotFound) {
+					return "", fmt.Errorf("getting secret %s failed: %w", key, err)
+				}
+
+				Warning(cmd, "secret %s not found, rendering it as an empty string\n", key)
+			}
+
+			cache[key] = string(item.Data)
+			return cache[key], nil
+		},
+		"env": os.Getenv,
+		"b64enc": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+		"b64dec": func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			return string(b), err
+		},
+		"json": func(s string) (string, error) {
+			b, err := json.Marshal(s)
+			return string(b), err
+		},
+		"trim": strings.TrimSpace,
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	service := os.Getenv("OSV_SERVICE")
+	renderCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
+	renderCmd.Flags().StringP("output", "o", "", "Write the result to this file instead of stdout")
+	renderCmd.Flags().Bool("strict", false, "Fail when a referenced secret does not exist")
+}
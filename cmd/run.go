@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+
+	"github.com/frostyeti/go/dotenv"
+	"github.com/frostyeti/osv/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run [--key <key>]... -- <command> [args...]",
+	Short: "Run a command with secrets injected into its environment",
+	Long: `Run a command with one or more secrets injected into its environment.
+
+Secrets are fetched from the OS keyring and exposed to the child process only,
+so they never end up in the parent shell or its history. Secret names are
+converted to SCREAMING_SNAKE_CASE to form environment variable names, e.g.
+"db-password" becomes DB_PASSWORD.
+
+Variables can also be loaded from dotenv files with --env-file. Values in
+these files may contain osv:// secret references (see osv inject), which are
+resolved before the child starts. Secrets given with --key take precedence
+over variables from env files.
+
+Credential files configured for osv credfile can be provided with
+--credfile. Each is written to a private temporary directory, exported in the
+variable its tool reads, and removed when the child exits. As with osv
+credfile, the directory must be memory-backed unless --allow-disk is set.
+
+Signals sent to osv, such as SIGTERM or SIGHUP, are forwarded to the child
+process. Ctrl-C and Ctrl-\ in a terminal already reach the child directly and
+are not sent a second time. osv exits with the child's exit code.
+
+Examples:
+  # Run a server with two secrets in its environment
+  osv run --key db-password --key api-token -- ./server
+
+  # Keys may also be given as positional arguments before the --
+  osv run db-password api-token -- ./server --port 8080
+
+  # Load a .env file whose values reference secrets, e.g.
+  # API_TOKEN=osv://my-service/api-token
+  osv run --env-file .env -- ./server
+
+  # Give psql a .pgpass built from the credfile.pgpass config value
+  osv run --credfile pgpass -- psql -h db.example.com app`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keys, _ := cmd.Flags().GetStringSlice("key")
+		envFiles, _ := cmd.Flags().GetStringArray("env-file")
+		credfiles, _ := cmd.Flags().GetStringArray("credfile")
+
+		command := args
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			keys = append(keys, args[:dash]...)
+			command = args[dash:]
+		}
+
+		if len(command) == 0 {
+			Error(cmd, "a command must be provided after --\n")
+			osExit(1)
+		}
+
+		if len(keys) == 0 && len(envFiles) == 0 && len(credfiles) == 0 {
+			Error(cmd, "at least one --key, --env-file or --credfile must be provided\n")
+			osExit(1)
+		}
+
+		env := os.Environ()
+		resolver := newRefResolver()
+		for _, path := range envFiles {
+			vars, err := loadEnvFile(path, resolver)
+			if err != nil {
+				Error(cmd, "loading env file %s failed: %v\n", path, err)
+				osExit(1)
+			}
+
+			env = append(env, vars...)
+		}
+
+		var files []*materialized
+		removeFiles := func() {
+			for _, m := range files {
+				m.remove(cmd)
+			}
+		}
+
+		if len(keys) > 0 || len(credfiles) > 0 {
+			kr, err := openKeyring(cmd)
+			if err != nil {
+				Error(cmd, "opening keyring failed: %v\n", err)
+				osExit(1)
+			}
+
+			for _, key := range keys {
+				item, err := kr.Get(key)
+				if err != nil {
+					Error(cmd, "getting secret %s failed: %v\n", key, err)
+					osExit(1)
+				}
+
+				env = append(env, utils.ScreamingSnakeCase(key)+"="+string(item.Data))
+			}
+
+			for _, typeName := range credfiles {
+				path, m, err := prepareCredfile(cmd, typeName, kr)
+				if err != nil {
+					removeFiles()
+					Error(cmd, "%v\n", err)
+					osExit(1)
+				}
+
+				files = append(files, m)
+				env = append(env, path)
+			}
+		}
+
+		code := runChild(cmd, command, env)
+		removeFiles()
+		osExit(code)
+	},
+}
+
+// loadEnvFile reads a dotenv file and returns its variables as NAME=value
+// pairs, with secret references in the values resolved.
+func loadEnvFile(path string, resolver *refResolver) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := dotenv.Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var vars []string
+	for _, name := range doc.Keys() {
+		raw, _ := doc.Get(name)
+		value, err := resolver.replace(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		vars = append(vars, name+"="+value)
+	}
+
+	return vars, nil
+}
+
+// runChild executes command with the given environment, wiring it to the
+// standard streams and forwarding signals sent to osv until it exits. It
+// returns the exit code osv should exit with.
+func runChild(cmd *cobra.Command, command []string, env []string) int {
+	child := exec.Command(command[0], command[1:]...)
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	if err := child.Start(); err != nil {
+		Error(cmd, "starting %s failed: %v\n", command[0], err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return 127
+		}
+
+		return 126
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, slices.Concat(forwardedSignals, caughtSignals)...)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	go func() {
+		for sig := range signals {
+			if slices.Contains(forwardedSignals, sig) {
+				_ = child.Process.Signal(sig)
+			}
+		}
+	}()
+
+	err := child.Wait()
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr.ProcessState)
+	}
+
+	Error(cmd, "running %s failed: %v\n", command[0], err)
+	return 1
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	service := os.Getenv("OSV_SERVICE")
+	runCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
+	runCmd.Flags().StringSliceP("key", "k", []string{}, "Name of secret(s) to inject (can be specified multiple times)")
+	runCmd.Flags().StringArray("env-file", []string{}, "Load variables from a dotenv file, resolving osv:// references (can be specified multiple times)")
+	runCmd.Flags().StringArray("credfile", []string{}, "Provide a credential file configured with credfile.<type> for the command's lifetime (can be specified multiple times)")
+	runCmd.Flags().Bool("allow-disk", false, "Allow writing --credfile files to a directory that is not memory-backed")
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// forwardedSignals are relayed from osv to child processes. Only signals that
+// are sent to osv itself are relayed; see caughtSignals.
+var forwardedSignals = []os.Signal{
+	syscall.SIGTERM,
+	syscall.SIGHUP,
+	syscall.SIGUSR1,
+	syscall.SIGUSR2,
+}
+
+// caughtSignals are caught so osv outlives the child, but not relayed. The
+// child shares osv's process group, so the terminal already delivers Ctrl-C
+// and Ctrl-\ to it, and relaying them would deliver them twice.
+var caughtSignals = []os.Signal{
+	syscall.SIGINT,
+	syscall.SIGQUIT,
+}
+
+// exitCode maps a child's process state to an exit code, following the shell
+// convention of 128+n for children terminated by signal n.
+func exitCode(state *os.ProcessState) int {
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+
+	return state.ExitCode()
+}
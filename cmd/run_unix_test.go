@@ -0,0 +1,25 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+func TestRunRelaysOnlySignalsSentToOSV(t *testing.T) {
+	mk, _, _ := setupTest(t)
+	_ = mk.Set(keyring.Item{Key: "token", Data: []byte("tok")})
+
+	script := `trap 'echo got-int' INT; trap 'echo got-term' TERM
+sleep 0.2; kill -INT $PPID; kill -TERM $PPID; sleep 0.3; echo done`
+	out, errOut, _ := executeCommand("run", "--key", "token", "--", "sh", "-c", script)
+	if strings.Contains(out, "got-int") {
+		t.Errorf("Expected SIGINT not to be relayed to the child, got: %q err: %s", out, errOut)
+	}
+	if !strings.Contains(out, "got-term") || !strings.Contains(out, "done") {
+		t.Errorf("Expected SIGTERM to be relayed to the child, got: %q err: %s", out, errOut)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package cmd
+
+import (
+	"os"
+)
+
+// forwardedSignals are relayed from osv to child processes. Windows has no
+// signals that can be relayed.
+var forwardedSignals = []os.Signal{}
+
+// caughtSignals are caught so osv outlives the child; console interrupts
+// already reach the child directly.
+var caughtSignals = []os.Signal{
+	os.Interrupt,
+}
+
+// exitCode maps a child's process state to an exit code.
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -4,11 +4,16 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"crypto/subtle"
+	"errors"
 	"io"
 	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/99designs/keyring"
 	"github.com/frostyeti/go/secrets"
+	"github.com/frostyeti/osv/format"
 	"github.com/spf13/cobra"
 )
 
@@ -21,12 +26,35 @@ var setCmd = &cobra.Command{
 The key can be provided as a positional argument or via the --key flag.
 The value can be provided by a positional argument or through one of several options
 
-The value can be provided through one of five exclusive options:
+The value can be provided through one of seven exclusive options:
   --value      Provide the value directly on the command line
   --file       Read the value from a file
   --var        Read the value from an environment variable
   --stdin      Read the value from standard input
   --generate   Generate a random secret 
+  --prompt     Prompt for the value twice without echoing it
+  --exec       Capture the standard output of a command
+
+Commands given with --exec run without a shell unless --shell is set; quotes
+and backslashes are honoured but nothing is expanded. A non-zero exit status
+fails the command, and --jq extracts a field such as .token when the output is
+JSON. The captured value is never printed.
+
+Values read with --file or --stdin often end with a newline that is not part
+of the secret. A single line read from stdin has its trailing newline removed
+unless --keep-newline is set. Use --trim-newline to remove one trailing
+newline from any value, or --trim to remove all surrounding whitespace.
+
+By default an existing secret is replaced. In shared scripts the write can be
+made conditional:
+  --if-absent             Leave an existing secret unchanged and exit 0
+  --no-overwrite          Fail with exit code 4 if the secret exists
+  --expect-fingerprint    Only replace the secret if its current value has
+                          this fingerprint (see 'osv get --format fingerprint'),
+                          otherwise fail with exit code 4
+
+When no option is given and stdin is a terminal, osv prompts for the value
+as with --prompt. Prompting is refused in non-interactive sessions.
   
 
 When using --generate, additional options control the generated secret:
@@ -38,7 +66,15 @@ When using --generate, additional options control the generated secret:
   --special    Specify custom special characters (default: @_-{}|#!~:^)
   --chars      Use only these specific characters (overrides other character options)
 
+Binary values such as keystores or DER certificates can be provided base64
+or hex encoded with --encoding. The decoded bytes are stored and the encoding
+is recorded with the secret, so 'osv get' can warn before printing them to a
+terminal.
+
 Examples:
+  # Type a secret at a hidden prompt
+  osv set my-secret
+
   # Set a secret with a value from command line
   osv set --key my-secret --value "secret-value"
 
@@ -51,8 +87,25 @@ Examples:
   # Set a secret with a value from stdin
   echo "secret-value" | osv set --key my-secret --stdin
 
+  # Drop the newline editors add at the end of files
+  osv set --key my-secret --file ./secret.txt --trim-newline
+
   # Generate a random 32-character secret
-  osv set --key my-secret --generate --size 32`,
+  osv set --key my-secret --generate --size 32
+
+  # Store a token produced by another CLI
+  osv set --key gh-token --exec "gh auth token"
+  osv set --key aws-session --exec "aws sts get-session-token --output json" --jq .Credentials.SessionToken
+
+  # Generate a token once, keeping it on later runs of a bootstrap script
+  osv set --key api-token --generate --if-absent
+
+  # Rotate a token only if nobody else changed it in the meantime
+  old=$(osv get api-token --format fingerprint)
+  osv set --key api-token --generate --expect-fingerprint "$old"
+
+  # Store a binary keystore
+  base64 < keystore.p12 | osv set --key keystore --stdin --encoding base64`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		key, _ := cmd.Flags().GetString("key")
@@ -61,6 +114,17 @@ Examples:
 		varName, _ := cmd.Flags().GetString("var")
 		stdin, _ := cmd.Flags().GetBool("stdin")
 		generate, _ := cmd.Flags().GetBool("generate")
+		encoding, _ := cmd.Flags().GetString("encoding")
+		prompt, _ := cmd.Flags().GetBool("prompt")
+		execCommand, _ := cmd.Flags().GetString("exec")
+		useShell, _ := cmd.Flags().GetBool("shell")
+		jqPath, _ := cmd.Flags().GetString("jq")
+		trimNewline, _ := cmd.Flags().GetBool("trim-newline")
+		keepNewline, _ := cmd.Flags().GetBool("keep-newline")
+		trim, _ := cmd.Flags().GetBool("trim")
+		ifAbsent, _ := cmd.Flags().GetBool("if-absent")
+		noOverwrite, _ := cmd.Flags().GetBool("no-overwrite")
+		expectFingerprint, _ := cmd.Flags().GetString("expect-fingerprint")
 
 		l := len(args)
 		if l > 0 {
@@ -87,14 +151,29 @@ Examples:
 		if generate {
 			inputMethods++
 		}
+		if prompt {
+			inputMethods++
+		}
+		if execCommand != "" {
+			inputMethods++
+		}
 
 		if inputMethods == 0 {
-			Error(cmd, "must specify exactly one of --value, --file, --var, --stdin, or --generate\n")
-			osExit(1)
+			if !stdinIsTerminal() {
+				Error(cmd, "must specify exactly one of --value, --file, --var, --stdin, --generate, --prompt or --exec\n")
+				osExit(1)
+			}
+
+			prompt = true
 		}
 
 		if inputMethods > 1 {
-			Error(cmd, "options --value, --file, --var, --stdin, and --generate are mutually exclusive\n")
+			Error(cmd, "options --value, --file, --var, --stdin, --generate, --prompt and --exec are mutually exclusive\n")
+			osExit(1)
+		}
+
+		if (useShell || jqPath != "") && execCommand == "" {
+			Error(cmd, "--shell and --jq require --exec\n")
 			osExit(1)
 		}
 
@@ -104,12 +183,37 @@ Examples:
 			osExit(1)
 		}
 
+		if err := validateEncoding(encoding); err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
 		kr, err := openKeyring(cmd)
 		if err != nil {
 			Error(cmd, "opening keyring failed: %v\n", err)
 			osExit(1)
 		}
 
+		// Check before reading the value, so no prompt or command runs for a
+		// write that will not happen.
+		if ifAbsent || noOverwrite {
+			exists, err := secretExists(kr, key)
+			if err != nil {
+				Error(cmd, "getting secret %s failed: %v\n", key, err)
+				osExit(exitBackend)
+			}
+
+			if exists && ifAbsent {
+				Ok(cmd, "%s already exists, left unchanged.\n", key)
+				osExit(0)
+			}
+
+			if exists {
+				Error(cmd, "secret %s already exists\n", key)
+				osExit(exitConflict)
+			}
+		}
+
 		// Get the secret value based on the input method
 		var secretValue string
 		switch {
@@ -135,19 +239,86 @@ Examples:
 				osExit(1)
 			}
 			secretValue = string(data)
+			if !keepNewline && isSingleLine(secretValue) {
+				trimNewline = true
+			}
 		case generate:
 			secretValue, err = generateSecret(cmd)
 			if err != nil {
 				Error(cmd, "generating secret failed: %v\n", err)
 				osExit(1)
 			}
+		case prompt:
+			secretValue, err = promptSecret(key)
+			if err != nil {
+				Error(cmd, "%v\n", err)
+				osExit(1)
+			}
+		case execCommand != "":
+			data, err := captureOutput(execCommand, useShell)
+			if err != nil {
+				Error(cmd, "%v\n", err)
+				osExit(1)
+			}
+
+			secretValue = string(data)
+			if jqPath != "" {
+				secretValue, err = extractJSONField(data, jqPath)
+				if err != nil {
+					Error(cmd, "%v\n", err)
+					osExit(1)
+				}
+			} else if !keepNewline && isSingleLine(secretValue) {
+				trimNewline = true
+			}
+
+			if secretValue == "" {
+				Error(cmd, "the command produced no output\n")
+				osExit(1)
+			}
 		}
 
-		// Set the secret
-		err = kr.Set(keyring.Item{
+		switch {
+		case trim:
+			secretValue = strings.TrimSpace(secretValue)
+		case trimNewline:
+			secretValue = trimTrailingNewline(secretValue)
+		}
+
+		data, err := decodeValue(encoding, secretValue)
+		if err != nil {
+			Error(cmd, "%v\n", err)
+			osExit(1)
+		}
+
+		item := keyring.Item{
 			Key:  key,
-			Data: []byte(secretValue),
-		})
+			Data: data,
+		}
+		if encoding != "" && encoding != "raw" {
+			item.Description = encodingPrefix + encoding
+		}
+
+		// Compare as late as possible to keep the window for concurrent
+		// writers small.
+		if expectFingerprint != "" {
+			current, err := kr.Get(key)
+			if err != nil {
+				Error(cmd, "getting secret %s failed: %v\n", key, err)
+				if errors.Is(err, keyring.ErrKeyNotFound) {
+					osExit(exitMissing)
+				}
+				osExit(exitBackend)
+			}
+
+			if !fingerprintMatches(current.Data, expectFingerprint) {
+				Error(cmd, "secret %s has changed, its fingerprint is %s\n", key, format.Fingerprint(current.Data))
+				osExit(exitConflict)
+			}
+		}
+
+		// Set the secret
+		err = kr.Set(item)
 		if err != nil {
 			Error(cmd, "setting secret %s failed: %v\n", key, err)
 			osExit(1)
@@ -158,6 +329,43 @@ Examples:
 	},
 }
 
+// secretExists reports whether key is in kr.
+func secretExists(kr keyring.Keyring, key string) (bool, error) {
+	_, err := kr.Get(key)
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// fingerprintMatches compares the fingerprint of data with expected, which may
+// omit the sha256: prefix.
+func fingerprintMatches(data []byte, expected string) bool {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if !strings.HasPrefix(expected, "sha256:") {
+		expected = "sha256:" + expected
+	}
+
+	return subtle.ConstantTimeCompare([]byte(format.Fingerprint(data)), []byte(expected)) == 1
+}
+
+// trimTrailingNewline removes one trailing \n or \r\n from s.
+func trimTrailingNewline(s string) string {
+	s, ok := strings.CutSuffix(s, "\n")
+	if ok {
+		s = strings.TrimSuffix(s, "\r")
+	}
+
+	return s
+}
+
+// isSingleLine reports whether s is one line of text terminated by a
+// newline, such as the output of echo.
+func isSingleLine(s string) bool {
+	return strings.HasSuffix(s, "\n") && strings.Count(s, "\n") == 1 && utf8.ValidString(s)
+}
+
 func generateSecret(cmd *cobra.Command) (string, error) {
 	size, _ := cmd.Flags().GetInt("size")
 	noUpper, _ := cmd.Flags().GetBool("no-upper")
@@ -200,11 +408,23 @@ func init() {
 	setCmd.Flags().StringP("service", "s", service, "Service name for the keyring")
 	setCmd.Flags().StringP("key", "k", "", "The name of the secret to set (required)")
 
-	setCmd.Flags().String("value", "", "The secret value (exclusive with --file, --var, --stdin, --generate)")
-	setCmd.Flags().String("file", "", "Path to file containing the secret value (exclusive with --value, --var, --stdin, --generate)")
-	setCmd.Flags().String("var", "", "Environment variable name containing the secret value (exclusive with --value, --file, --stdin, --generate)")
-	setCmd.Flags().Bool("stdin", false, "Read the secret value from stdin (exclusive with --value, --file, --var, --generate)")
-	setCmd.Flags().BoolP("generate", "g", false, "Generate a random secret value (exclusive with --value, --file, --var, --stdin)")
+	setCmd.Flags().String("value", "", "The secret value")
+	setCmd.Flags().String("file", "", "Path to file containing the secret value")
+	setCmd.Flags().String("var", "", "Environment variable name containing the secret value")
+	setCmd.Flags().Bool("stdin", false, "Read the secret value from stdin")
+	setCmd.Flags().BoolP("generate", "g", false, "Generate a random secret value")
+	setCmd.Flags().BoolP("prompt", "p", false, "Prompt for the secret value without echo (default when stdin is a terminal and no other source is given)")
+	setCmd.Flags().String("exec", "", "Run a command and use its standard output as the secret value")
+	setCmd.Flags().Bool("shell", false, "Run the --exec command through the system shell")
+	setCmd.Flags().String("jq", "", "Extract a field from the JSON output of --exec, e.g. .token or .items[0].value")
+
+	setCmd.Flags().Bool("if-absent", false, "Leave an existing secret unchanged and exit successfully")
+	setCmd.Flags().Bool("no-overwrite", false, "Fail if the secret already exists")
+	setCmd.Flags().String("expect-fingerprint", "", "Only replace the secret if its current value has this fingerprint")
+	setCmd.Flags().Bool("trim-newline", false, "Remove one trailing newline from the value")
+	setCmd.Flags().Bool("keep-newline", false, "Store the value verbatim, even a single line read from stdin")
+	setCmd.Flags().Bool("trim", false, "Remove leading and trailing whitespace from the value")
+	setCmd.Flags().String("encoding", "", "Encoding of the provided value, decoded before storing: raw, base64 or hex")
 
 	// Generation options
 	setCmd.Flags().Int("size", 16, "Size of the generated secret in characters")
@@ -216,5 +436,7 @@ func init() {
 	setCmd.Flags().String("chars", "", "Use only these specific characters (overrides all other character options)")
 
 	// Mark the flags as mutually exclusive
-	setCmd.MarkFlagsMutuallyExclusive("value", "file", "var", "stdin", "generate")
+	setCmd.MarkFlagsMutuallyExclusive("value", "file", "var", "stdin", "generate", "prompt", "exec")
+	setCmd.MarkFlagsMutuallyExclusive("if-absent", "no-overwrite", "expect-fingerprint")
+	setCmd.MarkFlagsMutuallyExclusive("trim-newline", "keep-newline", "trim")
 }
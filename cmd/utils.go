@@ -13,6 +13,18 @@ import (
 
 var KeyringProvider = defaultOpenKeyring
 
+// ServiceKeyringProvider opens the keyring of an explicit service, optionally
+// restricted to one backend. It is used for secret references, which name
+// their service instead of relying on the --service flag or config default.
+var ServiceKeyringProvider = defaultOpenServiceKeyring
+
+// allowedBackends are the keyring backends osv is willing to use.
+var allowedBackends = []keyring.BackendType{
+	keyring.KeychainBackend,
+	keyring.WinCredBackend,
+	keyring.SecretServiceBackend,
+}
+
 func openKeyring(cmd *cobra.Command) (keyring.Keyring, error) {
 	return KeyringProvider(cmd)
 }
@@ -31,6 +43,12 @@ func defaultOpenKeyring(cmd *cobra.Command) (keyring.Keyring, error) {
 		}
 	}
 
+	return defaultOpenServiceKeyring(service, keyring.InvalidBackend)
+}
+
+func defaultOpenServiceKeyring(service string, backend keyring.BackendType) (keyring.Keyring, error) {
+	cfg, confErr := config.GetConfig()
+
 	libSecret := "login"
 	keychain := "login"
 
@@ -46,15 +64,16 @@ func defaultOpenKeyring(cmd *cobra.Command) (keyring.Keyring, error) {
 		}
 	}
 
+	backends := allowedBackends
+	if backend != keyring.InvalidBackend {
+		backends = []keyring.BackendType{backend}
+	}
+
 	kr, err := keyring.Open(keyring.Config{
 		ServiceName:             service,
 		LibSecretCollectionName: libSecret,
 		KeychainName:            keychain,
-		AllowedBackends: []keyring.BackendType{
-			keyring.KeychainBackend,
-			keyring.WinCredBackend,
-			keyring.SecretServiceBackend,
-		},
+		AllowedBackends:         backends,
 	})
 	return kr, err
 }
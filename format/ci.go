@@ -0,0 +1,248 @@
+package format
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/frostyeti/osv/internal/shell"
+)
+
+func init() {
+	Register(&builtin{
+		name:        "azure-devops",
+		aliases:     []string{"ado"},
+		description: "Azure DevOps secret task.setvariable logging commands",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			for _, e := range entries {
+				if _, err := fmt.Fprintf(w, "##vso[task.setvariable variable=%s;issecret=true]%s\n", e.Name, e.Value); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Register(&builtin{
+		name:        "github",
+		description: "Masks values and appends them to $GITHUB_ENV and/or $GITHUB_OUTPUT",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			return writeGitHub(w, opts.GitHubTarget, entries)
+		},
+	})
+
+	Register(&builtin{
+		name:        "gitlab",
+		description: "A GitLab dotenv report artifact",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			content, warnings, err := renderGitLabDotenv(entries)
+			if err != nil {
+				return err
+			}
+
+			for _, warning := range warnings {
+				opts.warn("%s", warning)
+			}
+
+			_, err = io.WriteString(w, content)
+			return err
+		},
+	})
+
+	Register(&builtin{
+		name:        "buildkite",
+		description: "A Buildkite environment hook that also redacts values",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			content, err := renderBuildkiteHook(entries)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.WriteString(w, content)
+			return err
+		},
+	})
+
+	Register(&builtin{
+		name:        "circleci",
+		description: "Appends export statements to $BASH_ENV",
+		format: func(_ io.Writer, entries []Entry, _ Options) error {
+			return appendBashEnv(entries)
+		},
+	})
+
+	Register(&builtin{
+		name:        "jenkins",
+		description: "A properties file for the Jenkins EnvInject plugin",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			_, err := io.WriteString(w, renderProperties(entries))
+			return err
+		},
+	})
+}
+
+// githubCommandEscaper escapes workflow command data, which the runner
+// unescapes before it registers a mask.
+var githubCommandEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// writeGitHub masks every entry in the GitHub Actions log and appends it to
+// the files named by GITHUB_ENV, GITHUB_OUTPUT or both, depending on target.
+// Multi-line values use a random heredoc delimiter that cannot collide with
+// the value, as GitHub recommends.
+func writeGitHub(w io.Writer, target string, entries []Entry) error {
+	var vars []string
+	switch target {
+	case "", "env":
+		vars = []string{"GITHUB_ENV"}
+	case "output":
+		vars = []string{"GITHUB_OUTPUT"}
+	case "both":
+		vars = []string{"GITHUB_ENV", "GITHUB_OUTPUT"}
+	default:
+		return fmt.Errorf("invalid --github-target %q (expected env, output or both)", target)
+	}
+
+	for _, e := range entries {
+		for _, line := range strings.Split(strings.ReplaceAll(e.Value, "\r\n", "\n"), "\n") {
+			if strings.TrimSpace(line) != "" {
+				fmt.Fprintf(w, "::add-mask::%s\n", githubCommandEscaper.Replace(line))
+			}
+		}
+	}
+
+	for _, name := range vars {
+		path := os.Getenv(name)
+		if path == "" {
+			return fmt.Errorf("%s environment variable is not set", name)
+		}
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("opening %s file failed: %w", name, err)
+		}
+
+		for _, e := range entries {
+			if err := writeGitHubVar(f, e.Name, e.Value); err != nil {
+				f.Close()
+				return fmt.Errorf("writing %s file failed: %w", name, err)
+			}
+		}
+
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing %s file failed: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeGitHubVar(w io.Writer, name, value string) error {
+	if !strings.ContainsAny(value, "\r\n") {
+		_, err := fmt.Fprintf(w, "%s=%s\n", name, value)
+		return err
+	}
+
+	delimiter, err := randomDelimiter(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
+	return err
+}
+
+// randomDelimiter returns a heredoc delimiter that does not occur in value.
+func randomDelimiter(value string) (string, error) {
+	for {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+
+		delimiter := "ghadelimiter_" + hex.EncodeToString(buf)
+		if !strings.Contains(value, delimiter) {
+			return delimiter, nil
+		}
+	}
+}
+
+// gitlabMaskable matches values GitLab is able to mask in job logs: a single
+// line of at least eight characters from the base64 alphabet plus @ : . ~.
+var gitlabMaskable = regexp.MustCompile(`^[A-Za-z0-9+/=@:.~_-]{8,}$`)
+
+// renderGitLabDotenv renders entries as a GitLab dotenv report artifact. GitLab
+// rejects multi-line values in these reports, so they are errors, while values
+// GitLab could not mask in logs are returned as warnings.
+func renderGitLabDotenv(entries []Entry) (string, []string, error) {
+	var b strings.Builder
+	var warnings []string
+	for _, e := range entries {
+		if !shell.IsValidName(e.Name) {
+			return "", nil, fmt.Errorf("%s is not a valid GitLab variable name", e.Name)
+		}
+
+		if strings.ContainsAny(e.Value, "\r\n\x00") {
+			return "", nil, fmt.Errorf("value of %s contains a line break or NUL byte, which GitLab dotenv reports cannot represent", e.Key)
+		}
+
+		if !gitlabMaskable.MatchString(e.Value) {
+			warnings = append(warnings, fmt.Sprintf("value of %s cannot be masked by GitLab", e.Key))
+		}
+
+		b.WriteString(e.Name + "=" + e.Value + "\n")
+	}
+
+	return b.String(), warnings, nil
+}
+
+// renderBuildkiteHook renders an environment hook for the Buildkite agent that
+// exports each entry and registers its value with the log redactor.
+func renderBuildkiteHook(entries []Entry) (string, error) {
+	var b strings.Builder
+	for _, e := range entries {
+		line, err := shell.Export(shell.Posix, e.Name, e.Value)
+		if err != nil {
+			return "", fmt.Errorf("formatting secret %s failed: %w", e.Key, err)
+		}
+
+		b.WriteString(line + "\n")
+		b.WriteString(`printf '%s' "$` + e.Name + `" | buildkite-agent redactor add` + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// appendBashEnv appends an export statement for each entry to the file named by
+// BASH_ENV, which CircleCI sources before every step.
+func appendBashEnv(entries []Entry) error {
+	path := os.Getenv("BASH_ENV")
+	if path == "" {
+		return fmt.Errorf("BASH_ENV environment variable is not set")
+	}
+
+	var b strings.Builder
+	for _, e := range entries {
+		line, err := shell.Export(shell.Posix, e.Name, e.Value)
+		if err != nil {
+			return fmt.Errorf("formatting secret %s failed: %w", e.Key, err)
+		}
+
+		b.WriteString(line + "\n")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("opening BASH_ENV file failed: %w", err)
+	}
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("writing BASH_ENV file failed: %w", err)
+	}
+
+	return f.Close()
+}
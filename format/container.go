@@ -0,0 +1,181 @@
+package format
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/frostyeti/osv/internal/utils"
+)
+
+func init() {
+	Register(&builtin{
+		name:        "k8s-secret",
+		aliases:     []string{"kubernetes-secret"},
+		description: "A Kubernetes Secret manifest keyed by variable name",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			manifest, err := renderK8sSecret(opts.K8s, entries)
+			if err != nil {
+				return fmt.Errorf("rendering Kubernetes secret failed: %w", err)
+			}
+
+			_, err = io.WriteString(w, manifest)
+			return err
+		},
+	})
+
+	Register(&builtin{
+		name:        "docker-env",
+		aliases:     []string{"docker"},
+		description: "A docker run --env-file file",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			content, err := renderDockerEnv(entries)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.WriteString(w, content)
+			return err
+		},
+	})
+
+	Register(&builtin{
+		name:        "compose-secrets",
+		description: "One file per secret in --dir, plus the compose secrets section",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			section, err := writeComposeSecrets(opts.Dir, entries)
+			if err != nil {
+				return fmt.Errorf("writing compose secrets failed: %w", err)
+			}
+
+			_, err = io.WriteString(w, section)
+			return err
+		},
+	})
+}
+
+// k8sKeyPattern matches valid keys of a Kubernetes Secret's data map.
+var k8sKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// renderK8sSecret renders a Kubernetes Secret manifest holding entries, keyed by
+// their variable names. Values go to base64 encoded data unless StringData is
+// set; values that are not valid UTF-8 always go to data.
+func renderK8sSecret(opts K8sOptions, entries []Entry) (string, error) {
+	if opts.Name == "" {
+		return "", fmt.Errorf("--name is required for the k8s-secret format")
+	}
+
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\n")
+	b.WriteString("kind: Secret\n")
+	b.WriteString("metadata:\n")
+	b.WriteString("  name: " + yamlString(opts.Name) + "\n")
+	if opts.Namespace != "" {
+		b.WriteString("  namespace: " + yamlString(opts.Namespace) + "\n")
+	}
+
+	if len(opts.Labels) > 0 {
+		b.WriteString("  labels:\n")
+		for _, label := range opts.Labels {
+			k, v, ok := strings.Cut(label, "=")
+			if !ok || k == "" {
+				return "", fmt.Errorf("invalid label %q (expected key=value)", label)
+			}
+
+			b.WriteString("    " + yamlString(k) + ": " + yamlString(v) + "\n")
+		}
+	}
+
+	secretType := opts.Type
+	if secretType == "" {
+		secretType = "Opaque"
+	}
+	b.WriteString("type: " + yamlString(secretType) + "\n")
+
+	var data, stringData strings.Builder
+	for _, e := range entries {
+		if !k8sKeyPattern.MatchString(e.Name) {
+			return "", fmt.Errorf("%s is not a valid Kubernetes secret key", e.Name)
+		}
+
+		if opts.StringData && utf8.ValidString(e.Value) {
+			stringData.WriteString("  " + yamlString(e.Name) + ": " + yamlString(e.Value) + "\n")
+		} else {
+			data.WriteString("  " + yamlString(e.Name) + ": " + base64.StdEncoding.EncodeToString([]byte(e.Value)) + "\n")
+		}
+	}
+
+	if data.Len() > 0 {
+		b.WriteString("data:\n")
+		b.WriteString(data.String())
+	}
+
+	if stringData.Len() > 0 {
+		b.WriteString("stringData:\n")
+		b.WriteString(stringData.String())
+	}
+
+	return b.String(), nil
+}
+
+// composeSecretPattern matches secret names accepted by Docker Compose, which
+// are also safe to use as file names.
+var composeSecretPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// renderDockerEnv renders entries in the syntax of docker run --env-file,
+// which takes every value verbatim up to the end of the line. Values with line
+// breaks or NUL bytes cannot be expressed and are rejected.
+func renderDockerEnv(entries []Entry) (string, error) {
+	var b strings.Builder
+	for _, e := range entries {
+		if e.Name == "" || strings.ContainsAny(e.Name, "= \t\r\n#") {
+			return "", fmt.Errorf("%s is not a valid docker env-file variable name", e.Name)
+		}
+
+		if strings.ContainsAny(e.Value, "\r\n\x00") {
+			return "", fmt.Errorf("value of %s contains a line break or NUL byte, which docker env-files cannot represent", e.Key)
+		}
+
+		b.WriteString(e.Name + "=" + e.Value + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// writeComposeSecrets writes each entry to dir as a file named after its
+// variable name, readable only by the current user, and returns the matching
+// top level secrets section for a compose file.
+func writeComposeSecrets(dir string, entries []Entry) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("--dir is required for the compose-secrets format")
+	}
+
+	for _, e := range entries {
+		if !composeSecretPattern.MatchString(e.Name) || e.Name == "." || e.Name == ".." {
+			return "", fmt.Errorf("%s is not a valid compose secret name", e.Name)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("secrets:\n")
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name)
+		if err := utils.WriteSecretFile(path, []byte(e.Value), 0600); err != nil {
+			return "", fmt.Errorf("writing %s failed: %w", path, err)
+		}
+
+		b.WriteString("  " + yamlString(e.Name) + ":\n")
+		b.WriteString("    file: " + yamlString(filepath.ToSlash(path)) + "\n")
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,370 @@
+package format
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func init() {
+	for _, f := range []struct {
+		name        string
+		aliases     []string
+		description string
+		render      func(*dataNode) (string, error)
+		// base64 marks formats that write binary values base64 encoded
+		// instead of mangling them.
+		base64 bool
+	}{
+		{"json", nil, "A JSON object keyed by secret name", func(n *dataNode) (string, error) { return renderJSON(n), nil }, true},
+		{"yaml", []string{"yml"}, "A YAML mapping keyed by secret name", func(n *dataNode) (string, error) { return renderYAML(n), nil }, false},
+		{"toml", nil, "A TOML document keyed by secret name", func(n *dataNode) (string, error) { return renderTOML(n), nil }, false},
+		{"tfvars", []string{"hcl"}, "A Terraform variables file keyed by secret name", renderTFVars, false},
+	} {
+		render, binary := f.render, f.base64
+		Register(&builtin{
+			name:        f.name,
+			aliases:     f.aliases,
+			description: f.description,
+			format: func(w io.Writer, entries []Entry, opts Options) error {
+				if binary {
+					entries = encodeBinary(entries, opts)
+				}
+
+				tree, err := buildDataTree(entries, opts.Nest)
+				if err != nil {
+					return err
+				}
+
+				content, err := render(tree)
+				if err != nil {
+					return err
+				}
+
+				_, err = io.WriteString(w, content)
+				return err
+			},
+		})
+	}
+
+	Register(&builtin{
+		name:        "properties",
+		description: "A Java properties file keyed by secret name",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			_, err := io.WriteString(w, renderProperties(keyedBySecret(entries)))
+			return err
+		},
+	})
+}
+
+// dataNode is a node in the tree rendered by the structured data formats. A
+// node either holds a value or children, never both.
+type dataNode struct {
+	Key      string
+	Value    string
+	Leaf     bool
+	Children []*dataNode
+}
+
+// buildDataTree arranges entries into a tree keyed by secret name, keeping the
+// order of entries. With a separator, names are split into nested objects, so
+// db.password becomes password inside db.
+func buildDataTree(entries []Entry, sep string) (*dataNode, error) {
+	root := &dataNode{}
+	for _, e := range entries {
+		path := []string{e.Key}
+		if sep != "" {
+			path = strings.Split(e.Key, sep)
+		}
+
+		node := root
+		for i, part := range path {
+			if part == "" {
+				return nil, fmt.Errorf("secret %s has an empty segment when split on %q", e.Key, sep)
+			}
+
+			child := node.child(part)
+			last := i == len(path)-1
+			if child == nil {
+				child = &dataNode{Key: part, Leaf: last}
+				node.Children = append(node.Children, child)
+			} else if child.Leaf || last {
+				return nil, fmt.Errorf("secret %s conflicts with another secret at %s", e.Key, strings.Join(path[:i+1], sep))
+			}
+
+			node = child
+		}
+
+		node.Value = e.Value
+	}
+
+	return root, nil
+}
+
+// encodeBinary returns entries with every value that is not valid UTF-8
+// replaced by its base64 encoding, warning about each one.
+func encodeBinary(entries []Entry, opts Options) []Entry {
+	out := make([]Entry, len(entries))
+	for i, e := range entries {
+		if !utf8.ValidString(e.Value) {
+			opts.warn("secret %s is binary and is written base64 encoded", e.Key)
+			e.Value = base64.StdEncoding.EncodeToString([]byte(e.Value))
+		}
+
+		out[i] = e
+	}
+
+	return out
+}
+
+func (n *dataNode) child(key string) *dataNode {
+	for _, c := range n.Children {
+		if c.Key == key {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// renderJSON renders the tree as an indented JSON object.
+func renderJSON(root *dataNode) string {
+	var b strings.Builder
+	writeJSONObject(&b, root, "")
+	b.WriteString("\n")
+	return b.String()
+}
+
+func writeJSONObject(b *strings.Builder, n *dataNode, indent string) {
+	if len(n.Children) == 0 {
+		b.WriteString("{}")
+		return
+	}
+
+	b.WriteString("{\n")
+	for i, c := range n.Children {
+		b.WriteString(indent + "  " + jsonString(c.Key) + ": ")
+		if c.Leaf {
+			b.WriteString(jsonString(c.Value))
+		} else {
+			writeJSONObject(b, c, indent+"  ")
+		}
+
+		if i < len(n.Children)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+// renderYAML renders the tree as a YAML mapping with every scalar quoted.
+func renderYAML(root *dataNode) string {
+	if len(root.Children) == 0 {
+		return "{}\n"
+	}
+
+	var b strings.Builder
+	writeYAMLMapping(&b, root, "")
+	return b.String()
+}
+
+func writeYAMLMapping(b *strings.Builder, n *dataNode, indent string) {
+	for _, c := range n.Children {
+		b.WriteString(indent + yamlString(c.Key) + ":")
+		if c.Leaf {
+			b.WriteString(" " + yamlString(c.Value) + "\n")
+		} else {
+			b.WriteString("\n")
+			writeYAMLMapping(b, c, indent+"  ")
+		}
+	}
+}
+
+// tomlBareKey matches keys TOML accepts without quotes.
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// renderTOML renders the tree as a TOML document. Values of a table come
+// before its sub-tables, as TOML requires.
+func renderTOML(root *dataNode) string {
+	var b strings.Builder
+	writeTOMLTable(&b, root, nil)
+	return b.String()
+}
+
+func writeTOMLTable(b *strings.Builder, n *dataNode, path []string) {
+	hasValues := false
+	for _, c := range n.Children {
+		if c.Leaf {
+			hasValues = true
+			break
+		}
+	}
+
+	if hasValues && len(path) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("[" + strings.Join(path, ".") + "]\n")
+	}
+
+	for _, c := range n.Children {
+		if c.Leaf {
+			b.WriteString(tomlKey(c.Key) + " = " + tomlString(c.Value) + "\n")
+		}
+	}
+
+	for _, c := range n.Children {
+		if !c.Leaf {
+			writeTOMLTable(b, c, append(path[:len(path):len(path)], tomlKey(c.Key)))
+		}
+	}
+}
+
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+
+	return tomlString(key)
+}
+
+// tomlString renders s as a TOML basic string. JSON escapes are valid in TOML
+// basic strings, which additionally forbid a raw DEL character.
+func tomlString(s string) string {
+	return strings.ReplaceAll(jsonString(s), "\x7f", `\u007f`)
+}
+
+// hclIdentifier matches HCL identifiers, the only names allowed for top level
+// variables in a tfvars file.
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// renderTFVars renders the tree as a Terraform variables file, with nested
+// nodes as object values.
+func renderTFVars(root *dataNode) (string, error) {
+	for _, c := range root.Children {
+		if !hclIdentifier.MatchString(c.Key) {
+			return "", fmt.Errorf("%s is not a valid Terraform variable name", c.Key)
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range root.Children {
+		b.WriteString(c.Key + " = ")
+		writeHCLValue(&b, c, "")
+		b.WriteString("\n")
+	}
+
+	return b.String(), nil
+}
+
+func writeHCLValue(b *strings.Builder, n *dataNode, indent string) {
+	if n.Leaf {
+		b.WriteString(hclString(n.Value))
+		return
+	}
+
+	b.WriteString("{\n")
+	for _, c := range n.Children {
+		key := c.Key
+		if !hclIdentifier.MatchString(key) {
+			key = hclString(key)
+		}
+
+		b.WriteString(indent + "  " + key + " = ")
+		writeHCLValue(b, c, indent+"  ")
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+// hclString renders s as an HCL quoted string. JSON escapes are valid in HCL,
+// but template sequences must also be escaped so they are not interpolated.
+func hclString(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(jsonString(s))
+}
+
+// renderProperties renders entries as a Java properties file keyed by entry
+// name. Callers choose the names, e.g. secret names for properties and
+// variable names for jenkins.
+func renderProperties(entries []Entry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(escapeProperty(e.Name, true) + "=" + escapeProperty(e.Value, false) + "\n")
+	}
+
+	return b.String()
+}
+
+// keyedBySecret returns a copy of entries whose variable names are replaced by
+// their secret names, for formats that key data by secret rather than by
+// environment variable.
+func keyedBySecret(entries []Entry) []Entry {
+	keyed := make([]Entry, len(entries))
+	for i, e := range entries {
+		e.Name = e.Key
+		keyed[i] = e
+	}
+
+	return keyed
+}
+
+// escapeProperty escapes s for a Java properties file using only ASCII, as
+// java.util.Properties.store does. Keys escape every space, values only a
+// leading one.
+func escapeProperty(s string, key bool) string {
+	var b strings.Builder
+	for i, c := range s {
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		case ' ':
+			if key || i == 0 {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(c)
+		default:
+			if c < 0x20 || c > 0x7e {
+				for _, u := range utf16.Encode([]rune{c}) {
+					fmt.Fprintf(&b, `\u%04X`, u)
+				}
+			} else {
+				b.WriteRune(c)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// yamlString renders s as a double quoted YAML scalar. JSON strings are valid
+// YAML, so the JSON encoder does the escaping.
+func yamlString(s string) string {
+	return jsonString(s)
+}
+
+// jsonString renders s as a JSON string without HTML escaping.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,119 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// ExternalPrefix is the file name prefix of external formatter executables.
+// The format name osv-format-foo is selected with --format foo.
+const ExternalPrefix = "osv-format-"
+
+// External is a formatter implemented by an executable on the PATH. It
+// receives the entries and options as a JSON document on stdin and writes the
+// formatted output to stdout.
+type External struct {
+	name string
+	path string
+}
+
+// externalInput is the document written to an external formatter's stdin.
+type externalInput struct {
+	Format  string  `json:"format"`
+	Entries []Entry `json:"entries"`
+	Options Options `json:"options"`
+}
+
+func (e *External) Name() string        { return e.name }
+func (e *External) Aliases() []string   { return nil }
+func (e *External) Description() string { return "external formatter " + e.path }
+
+// Path returns the location of the executable.
+func (e *External) Path() string { return e.path }
+
+func (e *External) Format(w io.Writer, entries []Entry, opts Options) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	input, err := json.Marshal(externalInput{Format: e.name, Entries: entries, Options: opts})
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(e.path)
+	c.Stdin = bytes.NewReader(input)
+	c.Stdout = w
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("external formatter %s failed: %w", e.path, err)
+	}
+
+	return nil
+}
+
+func lookupExternal(name string) (Formatter, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, false
+	}
+
+	path, err := exec.LookPath(ExternalPrefix + name)
+	if err != nil {
+		return nil, false
+	}
+
+	return &External{name: name, path: path}, true
+}
+
+// ExternalFormatters returns the external formatters found on the PATH, sorted
+// by name. When the same name occurs in several directories the first one
+// wins, and names taken by registered formatters are skipped, matching the
+// lookup done by Lookup.
+func ExternalFormatters() []*External {
+	seen := map[string]bool{}
+	var list []*External
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name, ok := strings.CutPrefix(file.Name(), ExternalPrefix)
+			if !ok || file.IsDir() {
+				continue
+			}
+
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+
+			mu.RLock()
+			_, registered := names[name]
+			mu.RUnlock()
+			if name == "" || seen[name] || registered {
+				continue
+			}
+
+			if f, ok := lookupExternal(name); ok {
+				seen[name] = true
+				list = append(list, f.(*External))
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+
+	return list
+}
@@ -0,0 +1,149 @@
+// Package format renders secrets in the output formats supported by osv. Each
+// format is a Formatter kept in a registry shared by the osv commands, and
+// programs embedding osv can register their own.
+package format
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Entry is a secret to render.
+type Entry struct {
+	// Key is the name of the secret in the keyring.
+	Key string `json:"key"`
+	// Name is the variable name the secret is exported under.
+	Name string `json:"name"`
+	// Value is the secret value.
+	Value string `json:"value"`
+}
+
+// Options holds the settings individual formats read. Formats ignore the
+// settings that do not apply to them.
+type Options struct {
+	// Nest splits secret names on this separator into nested objects.
+	Nest string `json:"nest,omitempty"`
+	// Dir is the directory formats that write files put them in.
+	Dir string `json:"dir,omitempty"`
+	// GitHubTarget selects the GitHub Actions files to write: env, output
+	// or both.
+	GitHubTarget string `json:"githubTarget,omitempty"`
+	// K8s describes the manifest of the k8s-secret format.
+	K8s K8sOptions `json:"k8s,omitempty"`
+	// Systemd controls the systemd-creds format.
+	Systemd SystemdOptions `json:"systemd,omitempty"`
+	// Hash controls the password hash formats.
+	Hash HashOptions `json:"hash,omitempty"`
+	// Warn receives non-fatal problems found while formatting.
+	Warn func(msg string) `json:"-"`
+}
+
+// K8sOptions describes a Kubernetes Secret manifest.
+type K8sOptions struct {
+	Name       string   `json:"name,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	StringData bool     `json:"stringData,omitempty"`
+}
+
+// SystemdOptions controls the systemd-creds format.
+type SystemdOptions struct {
+	// Owner is the user[:group] credential files are given to.
+	Owner string `json:"owner,omitempty"`
+	// DropIn renders a unit drop-in with SetCredential= lines instead of
+	// writing credential files.
+	DropIn bool `json:"dropIn,omitempty"`
+}
+
+func (o Options) warn(format string, a ...interface{}) {
+	if o.Warn != nil {
+		o.Warn(fmt.Sprintf(format, a...))
+	}
+}
+
+// Formatter renders entries in one output format.
+type Formatter interface {
+	// Name is the name the format is selected by.
+	Name() string
+	// Aliases are alternative names for the format.
+	Aliases() []string
+	// Description is a one line summary shown by osv formats.
+	Description() string
+	// Format writes entries to w. Formats that write elsewhere, such as
+	// CI files, may write nothing to w.
+	Format(w io.Writer, entries []Entry, opts Options) error
+}
+
+var (
+	mu      sync.RWMutex
+	formats = map[string]Formatter{}
+	names   = map[string]Formatter{}
+)
+
+// Register adds f to the registry. It panics if f's name or one of its aliases
+// is already taken.
+func Register(f Formatter) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, name := range append([]string{f.Name()}, f.Aliases()...) {
+		if _, ok := names[name]; ok {
+			panic("format: Register called twice for " + name)
+		}
+	}
+
+	formats[f.Name()] = f
+	for _, name := range append([]string{f.Name()}, f.Aliases()...) {
+		names[name] = f
+	}
+}
+
+// Lookup returns the formatter registered under name or one of its aliases.
+// Names that are not registered are looked up as external formatters on the
+// PATH.
+func Lookup(name string) (Formatter, bool) {
+	mu.RLock()
+	f, ok := names[name]
+	mu.RUnlock()
+	if ok {
+		return f, true
+	}
+
+	return lookupExternal(name)
+}
+
+// Formatters returns the registered formatters sorted by name.
+func Formatters() []Formatter {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]Formatter, 0, len(formats))
+	for _, f := range formats {
+		list = append(list, f)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+
+	return list
+}
+
+// builtin is a Formatter backed by a function.
+type builtin struct {
+	name        string
+	aliases     []string
+	description string
+	format      func(w io.Writer, entries []Entry, opts Options) error
+}
+
+func (b *builtin) Name() string        { return b.name }
+func (b *builtin) Aliases() []string   { return b.aliases }
+func (b *builtin) Description() string { return b.description }
+
+func (b *builtin) Format(w io.Writer, entries []Entry, opts Options) error {
+	return b.format(w, entries, opts)
+}
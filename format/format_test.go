@@ -0,0 +1,199 @@
+package format
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLookupAliases(t *testing.T) {
+	for alias, name := range map[string]string{"bash": "sh", "pwsh": "powershell", "yml": "yaml", ".env": "dotenv"} {
+		f, ok := Lookup(alias)
+		if !ok || f.Name() != name {
+			t.Errorf("Lookup(%q) = %v, want %s", alias, f, name)
+		}
+	}
+
+	if _, ok := Lookup("does-not-exist"); ok {
+		t.Errorf("expected unknown format to be missing")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected Register to panic for a taken alias")
+		}
+	}()
+
+	Register(&builtin{
+		name:    "json-duplicate",
+		aliases: []string{"yml"},
+		format:  func(io.Writer, []Entry, Options) error { return nil },
+	})
+}
+
+func TestFormattersSorted(t *testing.T) {
+	list := Formatters()
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Name() >= list[i].Name() {
+			t.Fatalf("formatters not sorted: %s before %s", list[i-1].Name(), list[i].Name())
+		}
+	}
+}
+
+func TestExternalFormatter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncat\n"
+	if err := os.WriteFile(filepath.Join(dir, ExternalPrefix+"echo"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	f, ok := Lookup("echo")
+	if !ok {
+		t.Fatalf("expected external formatter to be found")
+	}
+
+	var out bytes.Buffer
+	err := f.Format(&out, []Entry{{Key: "db-pass", Name: "DB_PASS", Value: "x"}}, Options{Nest: "."})
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var input externalInput
+	if err := json.Unmarshal(out.Bytes(), &input); err != nil {
+		t.Fatalf("external formatter did not receive JSON: %v\n%s", err, out.String())
+	}
+	if input.Format != "echo" || input.Options.Nest != "." || len(input.Entries) != 1 || input.Entries[0] != (Entry{Key: "db-pass", Name: "DB_PASS", Value: "x"}) {
+		t.Errorf("unexpected input to external formatter: %s", out.String())
+	}
+
+	external := ExternalFormatters()
+	if len(external) == 0 || external[0].Name() != "echo" {
+		t.Errorf("expected echo in external formatters, got %v", external)
+	}
+}
+
+func TestSystemdCredentials(t *testing.T) {
+	f, _ := Lookup("systemd-creds")
+	entries := []Entry{{Key: "db-pass", Name: "db-pass", Value: " a%b\\c\n"}}
+
+	var out bytes.Buffer
+	if err := f.Format(&out, entries, Options{Systemd: SystemdOptions{DropIn: true}}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if want := "[Service]\nSetCredential=db-pass:\\x20a%%b\\\\c\\n\n"; out.String() != want {
+		t.Errorf("unexpected drop-in:\n%q\nwant:\n%q", out.String(), want)
+	}
+
+	dir := t.TempDir()
+	out.Reset()
+	if err := f.Format(&out, entries, Options{Dir: dir}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	path := filepath.Join(dir, "db-pass")
+	if out.String() != "LoadCredential=db-pass:"+path+"\n" {
+		t.Errorf("unexpected LoadCredential lines: %s", out.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != " a%b\\c\n" {
+		t.Errorf("unexpected credential file %q (%v)", data, err)
+	}
+
+	if runtime.GOOS != "windows" {
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0400 {
+			t.Errorf("expected mode 0400, got %v", info.Mode().Perm())
+		}
+	}
+
+	if err := f.Format(&out, []Entry{{Key: "x", Name: "../x"}}, Options{Dir: dir}); err == nil {
+		t.Errorf("expected an error for a credential name containing a slash")
+	}
+}
+
+func TestSHA512Crypt(t *testing.T) {
+	// Test vectors from the SHA-crypt specification.
+	tests := []struct {
+		password, salt string
+		rounds         int
+		want           string
+	}{
+		{"Hello world!", "saltstring", 0, "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		{"Hello world!", "saltstringsaltstring", 10000, "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+		{"This is just a test", "toolongsaltstring", 5000, "$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0"},
+		{"we have a short salt string but not a short password", "short", 77777, "$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0"},
+		{"the minimum number is still observed", "roundstoolow", 10, "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+	}
+
+	for _, tt := range tests {
+		if got := sha512Crypt(tt.password, tt.salt, tt.rounds); got != tt.want {
+			t.Errorf("sha512Crypt(%q, %q, %d) = %s, want %s", tt.password, tt.salt, tt.rounds, got, tt.want)
+		}
+	}
+}
+
+func TestPasswordHashFormats(t *testing.T) {
+	entries := []Entry{{Key: "admin", Name: "ADMIN", Value: "s3cret"}}
+
+	var buf bytes.Buffer
+	f, _ := Lookup("htpasswd")
+	if err := f.Format(&buf, entries, Options{Hash: HashOptions{Cost: 4}}); err != nil {
+		t.Fatal(err)
+	}
+
+	user, hash, _ := strings.Cut(strings.TrimSuffix(buf.String(), "\n"), ":")
+	if user != "admin" || !strings.HasPrefix(hash, "$2y$04$") {
+		t.Fatalf("unexpected htpasswd line: %q", buf.String())
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte("$2a$"+hash[4:]), []byte("s3cret")); err != nil {
+		t.Errorf("htpasswd hash does not match the secret: %v", err)
+	}
+
+	buf.Reset()
+	f, _ = Lookup("argon2id")
+	if err := f.Format(&buf, entries, Options{Hash: HashOptions{User: "grafana", Time: 1, Memory: 1024, Threads: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "$")
+	if len(parts) != 6 || parts[0] != "grafana:" || parts[1] != "argon2id" || parts[3] != "m=1024,t=1,p=1" {
+		t.Fatalf("unexpected argon2id line: %q", buf.String())
+	}
+	salt, _ := base64.RawStdEncoding.DecodeString(parts[4])
+	if got := base64.RawStdEncoding.EncodeToString(argon2.IDKey([]byte("s3cret"), salt, 1, 1024, 1, 32)); got != parts[5] {
+		t.Errorf("argon2id hash does not match the secret")
+	}
+
+	buf.Reset()
+	f, _ = Lookup("shadow")
+	if err := f.Format(&buf, entries, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	salt6 := strings.Split(line, "$")[2]
+	if line != sha512Crypt("s3cret", salt6, 0) || len(salt6) != 16 {
+		t.Errorf("unexpected sha512-crypt line: %q", line)
+	}
+
+	f, _ = Lookup("bcrypt")
+	if err := f.Format(io.Discard, entries, Options{Hash: HashOptions{User: "a:b"}}); err == nil {
+		t.Errorf("expected a user name with a colon to be rejected")
+	}
+}
@@ -0,0 +1,289 @@
+package format
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Default cost parameters of the password hash formats.
+const (
+	DefaultBcryptCost     = bcrypt.DefaultCost
+	DefaultSHACryptRounds = 5000
+	DefaultArgon2Time     = 3
+	DefaultArgon2Memory   = 64 * 1024
+	DefaultArgon2Threads  = 4
+)
+
+// HashOptions controls the password hash formats. Zero values select the
+// defaults above.
+type HashOptions struct {
+	// User is written before each hash as user:hash. The htpasswd format
+	// uses the secret name when it is empty.
+	User string `json:"user,omitempty"`
+	// Cost is the bcrypt cost, used by the bcrypt and htpasswd formats.
+	Cost int `json:"cost,omitempty"`
+	// Rounds is the number of sha512-crypt rounds.
+	Rounds int `json:"rounds,omitempty"`
+	// Time, Memory (in KiB) and Threads are the argon2id parameters.
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+func init() {
+	Register(&builtin{
+		name:        "htpasswd",
+		description: "Apache htpasswd lines with bcrypt hashes, for nginx and Apache basic auth",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			return writeHashes(w, entries, opts, true, func(password string) (string, error) {
+				h, err := hashBcrypt(password, opts.Hash.Cost)
+				if err != nil {
+					return "", err
+				}
+
+				// Apache documents the $2y$ prefix, which is the same algorithm.
+				return "$2y$" + strings.TrimPrefix(h, "$2a$"), nil
+			})
+		},
+	})
+
+	Register(&builtin{
+		name:        "bcrypt",
+		description: "bcrypt hash of each secret",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			return writeHashes(w, entries, opts, false, func(password string) (string, error) {
+				return hashBcrypt(password, opts.Hash.Cost)
+			})
+		},
+	})
+
+	Register(&builtin{
+		name:        "argon2id",
+		aliases:     []string{"argon2"},
+		description: "argon2id hash of each secret in PHC string format",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			return writeHashes(w, entries, opts, false, func(password string) (string, error) {
+				return hashArgon2id(password, opts.Hash)
+			})
+		},
+	})
+
+	Register(&builtin{
+		name:        "sha512-crypt",
+		aliases:     []string{"shadow"},
+		description: "SHA-512 crypt ($6$) hash of each secret, as used in /etc/shadow",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			return writeHashes(w, entries, opts, false, func(password string) (string, error) {
+				salt, err := randomSalt(16)
+				if err != nil {
+					return "", err
+				}
+
+				return sha512Crypt(password, salt, opts.Hash.Rounds), nil
+			})
+		},
+	})
+}
+
+// writeHashes writes one line per entry with the hash of its value, prefixed
+// with the user name when one is set or required.
+func writeHashes(w io.Writer, entries []Entry, opts Options, requireUser bool, hash func(string) (string, error)) error {
+	for _, e := range entries {
+		user := opts.Hash.User
+		if user == "" && requireUser {
+			user = e.Key
+		}
+
+		if strings.ContainsAny(user, ":\r\n") {
+			return fmt.Errorf("user name %q cannot contain colons or line breaks", user)
+		}
+
+		h, err := hash(e.Value)
+		if err != nil {
+			return fmt.Errorf("hashing secret %s failed: %w", e.Key, err)
+		}
+
+		if user != "" {
+			h = user + ":" + h
+		}
+
+		if _, err := fmt.Fprintln(w, h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hashBcrypt(password string, cost int) (string, error) {
+	if cost == 0 {
+		cost = DefaultBcryptCost
+	}
+
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return "", fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	h, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	return string(h), err
+}
+
+func hashArgon2id(password string, o HashOptions) (string, error) {
+	t, m, p := o.Time, o.Memory, o.Threads
+	if t == 0 {
+		t = DefaultArgon2Time
+	}
+	if m == 0 {
+		m = DefaultArgon2Memory
+	}
+	if p == 0 {
+		p = DefaultArgon2Threads
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, t, m, p, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, m, t, p,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// cryptAlphabet is the base64 alphabet of crypt(3) hashes and salts.
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func randomSalt(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	for i := range b {
+		b[i] = cryptAlphabet[int(b[i])%len(cryptAlphabet)]
+	}
+
+	return string(b), nil
+}
+
+// sha512Crypt implements the SHA-512 based crypt(3) scheme described in
+// https://www.akkadia.org/drepper/SHA-crypt.txt. A rounds value of zero
+// selects the default, which is then left out of the result.
+func sha512Crypt(password, salt string, rounds int) string {
+	explicit := rounds != 0
+	switch {
+	case rounds == 0:
+		rounds = DefaultSHACryptRounds
+	case rounds < 1000:
+		rounds = 1000
+	case rounds > 999999999:
+		rounds = 999999999
+	}
+
+	if len(salt) > 16 {
+		salt = salt[:16]
+	}
+
+	p, s := []byte(password), []byte(salt)
+
+	h := sha512.New()
+	h.Write(p)
+	h.Write(s)
+	h.Write(p)
+	b := h.Sum(nil)
+
+	h.Reset()
+	h.Write(p)
+	h.Write(s)
+	for n := len(p); n > 0; n -= 64 {
+		h.Write(b[:min(n, 64)])
+	}
+	for n := len(p); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(p)
+		}
+	}
+	a := h.Sum(nil)
+
+	h.Reset()
+	for range p {
+		h.Write(p)
+	}
+	pSeq := repeatTo(h.Sum(nil), len(p))
+
+	h.Reset()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(s)
+	}
+	sSeq := repeatTo(h.Sum(nil), len(s))
+
+	for i := 0; i < rounds; i++ {
+		h.Reset()
+		if i&1 != 0 {
+			h.Write(pSeq)
+		} else {
+			h.Write(a)
+		}
+		if i%3 != 0 {
+			h.Write(sSeq)
+		}
+		if i%7 != 0 {
+			h.Write(pSeq)
+		}
+		if i&1 != 0 {
+			h.Write(a)
+		} else {
+			h.Write(pSeq)
+		}
+		a = h.Sum(a[:0])
+	}
+
+	var out strings.Builder
+	out.WriteString("$6$")
+	if explicit {
+		out.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	out.WriteString(salt + "$")
+
+	// The digest bytes are encoded in groups of three in a fixed,
+	// rotating order.
+	for i := 0; i < 21; i++ {
+		x, y, z := a[i], a[i+21], a[i+42]
+		switch i % 3 {
+		case 1:
+			x, y, z = y, z, x
+		case 2:
+			x, y, z = z, x, y
+		}
+		encodeCrypt64(&out, uint(x)<<16|uint(y)<<8|uint(z), 4)
+	}
+	encodeCrypt64(&out, uint(a[63]), 2)
+
+	return out.String()
+}
+
+func repeatTo(b []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, b[:min(len(b), n-len(out))]...)
+	}
+
+	return out
+}
+
+func encodeCrypt64(out *strings.Builder, v uint, n int) {
+	for ; n > 0; n-- {
+		out.WriteByte(cryptAlphabet[v&0x3f])
+		v >>= 6
+	}
+}
@@ -0,0 +1,52 @@
+package format
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/frostyeti/osv/internal/shell"
+)
+
+func init() {
+	for _, f := range []struct {
+		name        string
+		aliases     []string
+		dialect     shell.Dialect
+		description string
+	}{
+		{"sh", []string{"bash", "zsh"}, shell.Posix, "POSIX shell export statements, for eval"},
+		{"powershell", []string{"pwsh"}, shell.PowerShell, "PowerShell $Env: assignments"},
+		{"fish", nil, shell.Fish, "fish set -gx statements"},
+		{"nu", []string{"nushell"}, shell.Nu, "nushell load-env records"},
+		{"cmd", []string{"bat"}, shell.Cmd, `cmd.exe set "NAME=value" statements`},
+		{"elvish", nil, shell.Elvish, "elvish set-env statements"},
+		{"make", []string{"makefile"}, shell.Make, "GNU make export assignments"},
+	} {
+		dialect := f.dialect
+		Register(&builtin{
+			name:        f.name,
+			aliases:     f.aliases,
+			description: f.description,
+			format: func(w io.Writer, entries []Entry, _ Options) error {
+				return writeExports(w, dialect, entries)
+			},
+		})
+	}
+}
+
+// writeExports writes one statement per entry that exports it as an
+// environment variable, quoted for the given dialect.
+func writeExports(w io.Writer, dialect shell.Dialect, entries []Entry) error {
+	for _, e := range entries {
+		line, err := shell.Export(dialect, e.Name, e.Value)
+		if err != nil {
+			return fmt.Errorf("formatting secret %s failed: %w", e.Key, err)
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/frostyeti/osv/internal/utils"
+)
+
+func init() {
+	Register(&builtin{
+		name:        "systemd-creds",
+		aliases:     []string{"systemd"},
+		description: "One credential file per secret in --dir, plus LoadCredential= lines",
+		format: func(w io.Writer, entries []Entry, opts Options) error {
+			if opts.Systemd.DropIn {
+				content, err := renderSetCredentials(entries)
+				if err != nil {
+					return err
+				}
+
+				_, err = io.WriteString(w, content)
+				return err
+			}
+
+			lines, err := writeSystemdCredentials(opts.Dir, opts.Systemd.Owner, entries)
+			if err != nil {
+				return fmt.Errorf("writing systemd credentials failed: %w", err)
+			}
+
+			_, err = io.WriteString(w, lines)
+			return err
+		},
+	})
+}
+
+// validCredentialName reports whether name can be used as a systemd credential
+// name, which doubles as its file name.
+func validCredentialName(name string) bool {
+	return name != "" && name != "." && name != ".." && len(name) <= 255 &&
+		!strings.ContainsAny(name, "/\\:\x00")
+}
+
+// writeSystemdCredentials writes each entry to dir as a read-only credential
+// file, optionally owned by owner (user[:group]), and returns the
+// LoadCredential= lines for a unit using them.
+func writeSystemdCredentials(dir, owner string, entries []Entry) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("--dir is required for the systemd-creds format")
+	}
+
+	uid, gid := -1, -1
+	if owner != "" {
+		var err error
+		uid, gid, err = lookupOwner(owner)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for _, e := range entries {
+		if !validCredentialName(e.Name) {
+			return "", fmt.Errorf("%s is not a valid credential name", e.Name)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, e := range entries {
+		path := filepath.Join(abs, e.Name)
+		if err := utils.WriteSecretFile(path, []byte(e.Value), 0400); err != nil {
+			return "", fmt.Errorf("writing %s failed: %w", path, err)
+		}
+
+		if uid != -1 || gid != -1 {
+			if err := os.Lchown(path, uid, gid); err != nil {
+				return "", fmt.Errorf("changing owner of %s failed: %w", path, err)
+			}
+		}
+
+		b.WriteString("LoadCredential=" + e.Name + ":" + path + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// lookupOwner resolves user[:group] to numeric ids. Numeric ids are used as
+// is. Without a group only the user is changed.
+func lookupOwner(owner string) (int, int, error) {
+	userName, groupName, _ := strings.Cut(owner, ":")
+
+	uid := -1
+	if userName != "" {
+		id := userName
+		if _, err := strconv.Atoi(id); err != nil {
+			u, err := user.Lookup(userName)
+			if err != nil {
+				return 0, 0, err
+			}
+			id = u.Uid
+		}
+
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return 0, 0, fmt.Errorf("user %s has no numeric id", userName)
+		}
+		uid = n
+	}
+
+	gid := -1
+	if groupName != "" {
+		id := groupName
+		if _, err := strconv.Atoi(id); err != nil {
+			g, err := user.LookupGroup(groupName)
+			if err != nil {
+				return 0, 0, err
+			}
+			id = g.Gid
+		}
+
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return 0, 0, fmt.Errorf("group %s has no numeric id", groupName)
+		}
+		gid = n
+	}
+
+	return uid, gid, nil
+}
+
+// renderSetCredentials renders a unit drop-in that embeds each entry with
+// SetCredential=. The value is unescaped by systemd, so backslashes, control
+// characters and specifier percents are escaped, as is surrounding whitespace
+// the unit parser would otherwise strip.
+func renderSetCredentials(entries []Entry) (string, error) {
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	for _, e := range entries {
+		if !validCredentialName(e.Name) {
+			return "", fmt.Errorf("%s is not a valid credential name", e.Name)
+		}
+
+		b.WriteString("SetCredential=" + e.Name + ":" + escapeUnitValue(e.Value) + "\n")
+	}
+
+	return b.String(), nil
+}
+
+func escapeUnitValue(s string) string {
+	var b strings.Builder
+	last := len(s) - 1
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '%':
+			b.WriteString("%%")
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		case c == ' ' && (i == 0 || i == last):
+			b.WriteString(`\x20`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package format
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/frostyeti/go/dotenv"
+)
+
+func init() {
+	Register(&builtin{
+		name:        "text",
+		description: "Each value on its own line, in request order",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			for _, e := range entries {
+				if _, err := fmt.Fprintln(w, e.Value); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Register(&builtin{
+		name:        "fingerprint",
+		description: "The SHA-256 fingerprint of each value, for osv set --expect-fingerprint",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			for _, e := range entries {
+				if _, err := fmt.Fprintln(w, Fingerprint([]byte(e.Value))); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Register(&builtin{
+		name:        "null",
+		aliases:     []string{"null-terminated"},
+		description: "Each value terminated by a NUL byte, for xargs -0",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			for _, e := range entries {
+				if _, err := fmt.Fprintf(w, "%s\x00", e.Value); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Register(&builtin{
+		name:        "dotenv",
+		aliases:     []string{"env", ".env"},
+		description: "A .env file",
+		format: func(w io.Writer, entries []Entry, _ Options) error {
+			doc := dotenv.NewDoc()
+			for _, e := range entries {
+				doc.Set(e.Name, e.Value)
+			}
+			_, err := fmt.Fprintln(w, doc.String())
+			return err
+		},
+	})
+}
+
+// Fingerprint identifies a secret value without revealing it, as sha256:
+// followed by the hex encoded SHA-256 digest of the value.
+func Fingerprint(value []byte) string {
+	sum := sha256.Sum256(value)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,243 @@
+// Package shell quotes values and renders variable assignments for the shell
+// dialects osv can emit, so that output can be safely eval'd.
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dialect identifies a shell language and its quoting rules.
+type Dialect string
+
+const (
+	// Posix covers sh, bash, zsh and other POSIX compatible shells.
+	Posix Dialect = "posix"
+	// PowerShell covers Windows PowerShell and PowerShell Core.
+	PowerShell Dialect = "powershell"
+	// Fish is the fish shell.
+	Fish Dialect = "fish"
+	// Nu is nushell.
+	Nu Dialect = "nu"
+	// Cmd is cmd.exe batch syntax, assuming delayed expansion is disabled.
+	Cmd Dialect = "cmd"
+	// Elvish is the elvish shell.
+	Elvish Dialect = "elvish"
+	// Make is GNU make.
+	Make Dialect = "make"
+)
+
+var (
+	// ErrNulByte is returned for values containing a NUL byte, which no
+	// shell can store in a variable.
+	ErrNulByte = errors.New("value contains a NUL byte")
+	// ErrInvalidName is returned for variable names the dialect cannot
+	// assign to without quoting.
+	ErrInvalidName = errors.New("invalid variable name")
+	// ErrUnrepresentable is returned for values the dialect has no safe
+	// way to express, such as multi-line values in cmd.exe.
+	ErrUnrepresentable = errors.New("value cannot be represented")
+	// ErrUnknownDialect is returned for dialects this package does not know.
+	ErrUnknownDialect = errors.New("unknown shell dialect")
+)
+
+// Quote returns value encoded as it appears on the right hand side of an
+// assignment in the given dialect. For the shells this is a single literal
+// word with newlines and other control characters preserved verbatim.
+func Quote(d Dialect, value string) (string, error) {
+	if strings.IndexByte(value, 0) >= 0 {
+		return "", ErrNulByte
+	}
+
+	switch d {
+	case Posix:
+		return quotePosix(value), nil
+	case PowerShell:
+		return quotePowerShell(value), nil
+	case Fish:
+		return quoteFish(value), nil
+	case Nu:
+		return quoteNu(value), nil
+	case Cmd:
+		return escapeCmd(value)
+	case Elvish:
+		return quoteElvish(value), nil
+	case Make:
+		return escapeMake(value)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownDialect, d)
+	}
+}
+
+// Export returns a statement that sets the environment variable name to value
+// in the given dialect, without a trailing newline.
+func Export(d Dialect, name, value string) (string, error) {
+	if !IsValidName(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	quoted, err := Quote(d, value)
+	if err != nil {
+		return "", err
+	}
+
+	switch d {
+	case Posix:
+		return "export " + name + "=" + quoted, nil
+	case PowerShell:
+		return "$Env:" + name + " = " + quoted, nil
+	case Fish:
+		return "set -gx " + name + " " + quoted, nil
+	case Nu:
+		return "load-env { " + name + ": " + quoted + " }", nil
+	case Cmd:
+		return `set "` + name + "=" + quoted + `"`, nil
+	case Elvish:
+		return "set-env " + name + " " + quoted, nil
+	case Make:
+		return "export " + name + " := " + quoted, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownDialect, d)
+	}
+}
+
+// IsValidName reports whether name is a portable environment variable name:
+// a letter or underscore followed by letters, digits or underscores.
+func IsValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// quotePosix wraps value in single quotes, inside which POSIX shells treat
+// every character literally. Each embedded single quote closes the quoted
+// string, adds an escaped quote and reopens it.
+func quotePosix(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quotePowerShell wraps value in a verbatim (single quoted) string. PowerShell
+// also treats the typographic single quotes as delimiters, so every one of
+// them is doubled to escape it.
+func quotePowerShell(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('\'')
+	for _, c := range value {
+		switch c {
+		case '\'', '\u2018', '\u2019', '\u201A', '\u201B':
+			b.WriteRune(c)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// quoteFish wraps value in single quotes. Fish honours exactly two escapes
+// inside them, \' and \\, so those are the only characters rewritten.
+func quoteFish(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(value) + "'"
+}
+
+// quoteElvish wraps value in single quotes, where elvish treats everything
+// literally except a doubled quote, which stands for one quote.
+func quoteElvish(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// quoteNu renders value as a double quoted nushell string. Plain double quoted
+// strings do not interpolate, so only backslashes, quotes and control
+// characters need escapes.
+func quoteNu(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('"')
+	for _, c := range value {
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\u{%x}`, c)
+			} else {
+				b.WriteRune(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// escapeCmd escapes value for the inside of a set "NAME=value" statement in
+// a batch file. The surrounding quotes neutralise & | < > ^, leaving % as the
+// only active character, which is doubled. Embedded quotes would end the
+// quoted region and line breaks end the statement, so both are rejected.
+func escapeCmd(value string) (string, error) {
+	if strings.ContainsAny(value, "\"\r\n") {
+		return "", fmt.Errorf("%w in cmd: contains a double quote or line break", ErrUnrepresentable)
+	}
+
+	return strings.ReplaceAll(value, "%", "%%"), nil
+}
+
+// escapeMake escapes value for the right hand side of a simply expanded
+// (:=) make assignment. Dollars are doubled and comment characters escaped,
+// along with any backslashes right before them. Make strips leading
+// whitespace and joins lines ending in a backslash, so those cases are
+// protected with an empty $() reference.
+func escapeMake(value string) (string, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("%w in make: contains a line break", ErrUnrepresentable)
+	}
+
+	var b strings.Builder
+	if strings.HasPrefix(value, " ") || strings.HasPrefix(value, "\t") {
+		b.WriteString("$()")
+	}
+
+	backslashes := 0
+	for _, c := range value {
+		switch c {
+		case '\\':
+			backslashes++
+			b.WriteRune(c)
+			continue
+		case '#':
+			b.WriteString(strings.Repeat(`\`, backslashes))
+			b.WriteString(`\#`)
+		case '$':
+			b.WriteString("$$")
+		default:
+			b.WriteRune(c)
+		}
+		backslashes = 0
+	}
+
+	if backslashes > 0 {
+		b.WriteString("$()")
+	}
+
+	return b.String(), nil
+}
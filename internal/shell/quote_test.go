@@ -0,0 +1,188 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+var hostileValues = []string{
+	"",
+	"plain",
+	"it's",
+	"'; rm -rf / #",
+	"$(touch /tmp/pwned)",
+	"`id`",
+	"${HOME}",
+	"\\'",
+	"line1\nline2\n",
+	"trailing\\",
+	"tab\there",
+	"\u2019; Remove-Item -Recurse C:\\ #",
+	"\"double\"",
+}
+
+func TestQuotePosix(t *testing.T) {
+	cases := map[string]string{
+		"":       "''",
+		"plain":  "'plain'",
+		"it's":   `'it'\''s'`,
+		"$(id)":  "'$(id)'",
+		"a\nb":   "'a\nb'",
+		"''":     `''\'''\'''`,
+		"back\\": "'back\\'",
+	}
+
+	for in, want := range cases {
+		got, err := Quote(Posix, in)
+		if err != nil {
+			t.Fatalf("Quote(%q) failed: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("Quote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestQuotePosixRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	for _, v := range hostileValues {
+		line, err := Export(Posix, "OSV_VALUE", v)
+		if err != nil {
+			t.Fatalf("Export(%q) failed: %v", v, err)
+		}
+
+		out, err := exec.Command(sh, "-c", line+"\nprintf '%s' \"$OSV_VALUE\"").Output()
+		if err != nil {
+			t.Fatalf("evaluating %s failed: %v", line, err)
+		}
+		if string(out) != v {
+			t.Errorf("round trip of %q produced %q", v, out)
+		}
+	}
+}
+
+func TestQuotePowerShell(t *testing.T) {
+	cases := map[string]string{
+		"":              "''",
+		"it's":          "'it''s'",
+		"$env:PATH":     "'$env:PATH'",
+		"a\u2019b":      "'a\u2019\u2019b'",
+		"\u2018x\u201B": "'\u2018\u2018x\u201B\u201B'",
+		"a\nb":          "'a\nb'",
+	}
+
+	for in, want := range cases {
+		got, err := Quote(PowerShell, in)
+		if err != nil {
+			t.Fatalf("Quote(%q) failed: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("Quote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestQuoteRejectsNul(t *testing.T) {
+	for _, d := range []Dialect{Posix, PowerShell} {
+		if _, err := Quote(d, "a\x00b"); !errors.Is(err, ErrNulByte) {
+			t.Errorf("%s: expected ErrNulByte, got %v", d, err)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	got, _ := Export(Posix, "API_KEY", "x'y")
+	if got != `export API_KEY='x'\''y'` {
+		t.Errorf("unexpected posix export: %s", got)
+	}
+
+	got, _ = Export(PowerShell, "API_KEY", "x'y")
+	if got != "$Env:API_KEY = 'x''y'" {
+		t.Errorf("unexpected powershell export: %s", got)
+	}
+
+	for _, name := range []string{"", "1ABC", "A-B", "A B", "A;rm"} {
+		if _, err := Export(Posix, name, "v"); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expected ErrInvalidName for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestQuoteOtherDialects(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		in      string
+		want    string
+	}{
+		{Fish, `it's \n`, `'it\'s \\n'`},
+		{Fish, "a\nb", "'a\nb'"},
+		{Elvish, "it's $x", "'it''s $x'"},
+		{Nu, "say \"hi\"\\\n\x01", `"say \"hi\"\\\n\u{1}"`},
+		{Nu, "$(ls) (ls)", `"$(ls) (ls)"`},
+		{Cmd, "100% & | < > ^ !", "100%% & | < > ^ !"},
+		{Make, "$HOME # c", `$$HOME \# c`},
+		{Make, `a\#b`, `a\\\#b`},
+		{Make, "  lead", "$()  lead"},
+		{Make, `trail\`, `trail\$()`},
+	}
+
+	for _, c := range cases {
+		got, err := Quote(c.dialect, c.in)
+		if err != nil {
+			t.Fatalf("%s: Quote(%q) failed: %v", c.dialect, c.in, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: Quote(%q) = %s, want %s", c.dialect, c.in, got, c.want)
+		}
+	}
+}
+
+func TestQuoteUnrepresentable(t *testing.T) {
+	for _, c := range []struct {
+		dialect Dialect
+		in      string
+	}{
+		{Cmd, `say "hi"`},
+		{Cmd, "a\nb"},
+		{Make, "a\nb"},
+	} {
+		if _, err := Quote(c.dialect, c.in); !errors.Is(err, ErrUnrepresentable) {
+			t.Errorf("%s: expected ErrUnrepresentable for %q, got %v", c.dialect, c.in, err)
+		}
+	}
+}
+
+func TestExportMakeRoundTrip(t *testing.T) {
+	mk, err := exec.LookPath("make")
+	if err != nil {
+		t.Skip("make not available")
+	}
+
+	for _, v := range []string{"plain", "$HOME $(shell id)", `a\#b # c`, "  lead", `trail\`, "x := y"} {
+		line, err := Export(Make, "OSV_VALUE", v)
+		if err != nil {
+			t.Fatalf("Export(%q) failed: %v", v, err)
+		}
+
+		makefile := filepath.Join(t.TempDir(), "Makefile")
+		body := line + "\nall:\n\t@printf '%s' \"$$OSV_VALUE\"\n"
+		if err := os.WriteFile(makefile, []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := exec.Command(mk, "-s", "-f", makefile).Output()
+		if err != nil {
+			t.Fatalf("running make for %s failed: %v", line, err)
+		}
+		if string(out) != v {
+			t.Errorf("round trip of %q produced %q", v, out)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteSecretFile writes data to path with the given permissions. Any existing
+// file is removed first and the new one is created exclusively, so the write
+// never follows a symlink planted at path or inherits looser permissions.
+func WriteSecretFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it
+// over path, so readers see either the old or the new content and a failed
+// write leaves path untouched. The file gets the given permissions regardless
+// of the umask.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+	defer func() {
+		if tmp != "" {
+			os.Remove(tmp)
+		}
+	}()
+
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	tmp = ""
+	return nil
+}
+
+// ShredFile overwrites the content of the file at path with zeros, flushes it
+// to storage and removes it. Overwriting is best effort on copy-on-write and
+// journaling file systems, so secrets should still be kept on tmpfs.
+func ShredFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err == nil && info.Mode().IsRegular() {
+		_, err = f.Write(make([]byte, info.Size()))
+		if err == nil {
+			err = f.Sync()
+		}
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if rerr := os.Remove(path); err == nil {
+		err = rerr
+	}
+
+	return err
+}
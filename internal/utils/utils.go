@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"strings"
+
 	"github.com/99designs/keyring"
 	"github.com/spf13/cobra"
 )
@@ -50,3 +52,11 @@ func ScreamingSnakeCase(input string) string {
 	}
 	return output
 }
+
+func SnakeCase(input string) string {
+	return strings.ToLower(ScreamingSnakeCase(input))
+}
+
+func KebabCase(input string) string {
+	return strings.ReplaceAll(SnakeCase(input), "_", "-")
+}